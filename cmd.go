@@ -0,0 +1,684 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mewmew/orbitals/prune"
+	"github.com/mewmew/orbitals/sample"
+	"github.com/mewmew/orbitals/wave"
+	"github.com/pkg/errors"
+)
+
+// usage prints the usage of the orbitals tool to standard error.
+func usage() {
+	const use = `
+Generate 3D-models and plots of electron orbitals.
+
+Usage:
+
+	orbitals <command> [flags]
+
+Commands:
+
+	model    generate 3D-models of orbitals
+	hybrid   generate 3D-models of hybrid orbitals
+	plot     generate plot of radial probability of orbitals
+	info     print information about orbitals
+
+Orbitals are specified in spectroscopic notation (e.g. 3d_z2, 2p_x or 4f-3), by
+quantum numbers (e.g. n=3,l=2,m=-1), or by hybridization (e.g. sp3, sp3d2 or
+dsp2).
+
+Run "orbitals <command> -help" for the flags of each command.
+`
+	fmt.Fprintln(os.Stderr, use[1:])
+}
+
+// Sampler specifies the method used to generate 3D-models.
+type Sampler uint8
+
+// Samplers.
+const (
+	// CartesianSampler samples the probability on a uniform Cartesian grid.
+	CartesianSampler Sampler = iota
+	// SphericSampler samples the probability on a spherical grid.
+	SphericSampler
+	// RejectionSampler draws electron positions by rejection sampling.
+	RejectionSampler
+	// MetropolisSampler draws electron positions by the Metropolis–Hastings
+	// algorithm.
+	MetropolisSampler
+	// MeshSampler generates isosurfaces (boundary surfaces) by marching cubes.
+	MeshSampler
+)
+
+// String returns the string representation of the sampler.
+func (sampler Sampler) String() string {
+	switch sampler {
+	case CartesianSampler:
+		return "cartesian"
+	case SphericSampler:
+		return "spheric"
+	case RejectionSampler:
+		return "rejection"
+	case MetropolisSampler:
+		return "metropolis"
+	case MeshSampler:
+		return "mesh"
+	}
+	return fmt.Sprintf("Sampler(%d)", uint8(sampler))
+}
+
+// config specifies how 3D-models are generated.
+type config struct {
+	// Method used to generate 3D-models.
+	sampler Sampler
+	// Quantity sampled as probability of points.
+	mode sample.Mode
+	// Basis of the angular part of orbitals.
+	basis wave.Basis
+	// Pruning strategy of point models.
+	prune prune.Strategy
+	// Radius of the sampled region (see sample.Extent) in atomic units of
+	// length; 0 for the radius enclosing 99.9% of the probability.
+	extent float64
+	// Number of grid points per axis (Cartesian and mesh samplers) or radial
+	// samples (spherical sampler); 0 to choose from points.
+	grid int
+	// Target number of points of grid samplers; 0 for default.
+	points int
+	// Sampling scheme of directions (spherical sampler).
+	sphere sample.Sphere
+	// Approximate number of directions (spherical sampler); 0 for default.
+	directions int
+	// Fraction of probability enclosed by isosurfaces (mesh sampler).
+	enclosed float64
+	// Number of electron positions drawn (Monte Carlo samplers).
+	samples int
+	// Seed of the random number generator (Monte Carlo samplers).
+	seed int64
+	// Output format; "obj" or "json".
+	format string
+	// Colour the vertices of OBJ point models by the sign of psi; blue for
+	// positive lobes and red for negative lobes.
+	signColors bool
+	// Precede the points of output files by the metadata of the wave function;
+	// as comments in OBJ format, and as a leading {"Metadata": ...} line in JSON
+	// format.
+	metadata bool
+	// Output directory.
+	outDir string
+	// Rotation of orbitals before sampling; nil if not rotated.
+	rotation *wave.Rotation
+	// Number of concurrent workers, shared between 3D-models generated
+	// concurrently and the workers evaluating the grid of each 3D-model
+	// (Cartesian, spherical and mesh samplers; see runJobs); 0 for the number of
+	// CPUs.
+	workers int
+}
+
+// modelFlags holds the command line flags controlling 3D-model generation,
+// before validation.
+type modelFlags struct {
+	sampler    string
+	mode       string
+	basis      string
+	prune      string
+	threshold  float64
+	extent     float64
+	grid       int
+	points     int
+	sphere     string
+	directions int
+	enclosed   float64
+	samples    int
+	seed       int64
+	format     string
+	colors     bool
+	metadata   bool
+	outDir     string
+	euler      string
+	rotation   string
+	workers    int
+}
+
+// register registers the flags controlling 3D-model generation with fs.
+func (f *modelFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.sampler, "sampler", "cartesian", "sampler (cartesian, spheric, rejection, metropolis or mesh)")
+	fs.StringVar(&f.mode, "mode", "density", "sampling mode (density, radial or signed)")
+	fs.StringVar(&f.basis, "basis", "real", "basis of orbitals (real or complex)")
+	fs.StringVar(&f.prune, "prune", "mass", "pruning strategy of point models (abs, mass, topk or rel)")
+	fs.Float64Var(&f.threshold, "threshold", 0, "threshold of pruning strategy; probability (abs), enclosed fraction (mass), number of points (topk) or fraction of maximum (rel); 0 for default")
+	fs.Float64Var(&f.extent, "extent", 0, "radius of sampled region in pm (half side length of cube for cartesian, mesh, rejection and metropolis); 0 for radius enclosing 99.9% of probability")
+	fs.IntVar(&f.grid, "grid", 0, "number of grid points per axis (cartesian and mesh) or radial samples (spheric); 0 to choose from -points")
+	fs.IntVar(&f.points, "points", 0, "target number of grid points (cartesian, spheric and mesh); 0 for default")
+	fs.StringVar(&f.sphere, "sphere", "uniform", "sampling scheme of directions (spheric); uniform, fibonacci or healpix")
+	fs.IntVar(&f.directions, "directions", 0, "approximate number of directions (spheric); 0 for default")
+	fs.Float64Var(&f.enclosed, "enclosed", 0.9, "fraction of probability enclosed by isosurfaces (mesh)")
+	fs.IntVar(&f.samples, "samples", 100000, "number of electron positions (rejection and metropolis)")
+	fs.Int64Var(&f.seed, "seed", 1, "seed of random number generator (rejection and metropolis)")
+	fs.StringVar(&f.format, "format", "obj", "output format (obj or json)")
+	fs.BoolVar(&f.colors, "colors", true, "colour vertices by the sign of psi (obj)")
+	fs.BoolVar(&f.metadata, "metadata", true, `write metadata of orbitals; as comments (obj), or as a leading {"Metadata": ...} line before the points (json)`)
+	fs.StringVar(&f.outDir, "o", ".", "output directory")
+	fs.StringVar(&f.euler, "euler", "", `Euler angles in degrees (z-y-z convention) of the rotation of orbitals (e.g. "0,90,0")`)
+	fs.StringVar(&f.rotation, "rotation", "", "row-major rotation matrix of the rotation of orbitals (e.g. \"0,0,1,0,1,0,-1,0,0\")")
+	fs.IntVar(&f.workers, "workers", 0, "number of concurrent workers; 0 for the number of CPUs")
+}
+
+// config returns the validated configuration of the flags.
+func (f *modelFlags) config() (*config, error) {
+	sampler, err := parseSampler(f.sampler)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	mode, err := parseMode(f.mode)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	basis, err := parseBasis(f.basis)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	strategy, err := parsePruneStrategy(f.prune, f.threshold)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if f.extent < 0 {
+		return nil, errors.Errorf("invalid extent; expected extent >= 0, got %g", f.extent)
+	}
+	if f.grid != 0 && f.grid < 2 {
+		return nil, errors.Errorf("invalid grid size; expected grid >= 2, got %d", f.grid)
+	}
+	if f.points < 0 {
+		return nil, errors.Errorf("invalid number of points; expected points >= 0, got %d", f.points)
+	}
+	sphere, err := parseSphere(f.sphere)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if f.directions < 0 {
+		return nil, errors.Errorf("invalid number of directions; expected directions >= 0, got %d", f.directions)
+	}
+	if !(0 < f.enclosed && f.enclosed <= 1) {
+		return nil, errors.Errorf("invalid enclosed fraction; expected 0 < enclosed <= 1, got %g", f.enclosed)
+	}
+	if !(f.samples >= 1) {
+		return nil, errors.Errorf("invalid number of samples; expected samples >= 1, got %d", f.samples)
+	}
+	switch f.format {
+	case "obj", "json":
+	default:
+		return nil, errors.Errorf("invalid output format; expected obj or json, got %q", f.format)
+	}
+	if sampler == MeshSampler && f.format != "obj" {
+		return nil, errors.Errorf("invalid output format of %v sampler; expected obj, got %q", sampler, f.format)
+	}
+	rotation, err := parseRotation(f.euler, f.rotation)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if f.workers < 0 {
+		return nil, errors.Errorf("invalid number of workers; expected workers >= 0, got %d", f.workers)
+	}
+	if err := os.MkdirAll(f.outDir, 0755); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	conf := &config{
+		sampler:    sampler,
+		mode:       mode,
+		basis:      basis,
+		prune:      strategy,
+		extent:     f.extent * wave.Picometer,
+		grid:       f.grid,
+		points:     f.points,
+		sphere:     sphere,
+		directions: f.directions,
+		enclosed:   f.enclosed,
+		samples:    f.samples,
+		seed:       f.seed,
+		format:     f.format,
+		signColors: f.colors,
+		metadata:   f.metadata,
+		outDir:     f.outDir,
+		rotation:   rotation,
+		workers:    f.workers,
+	}
+	return conf, nil
+}
+
+// orbitalFlags holds the command line flags specifying an orbital.
+type orbitalFlags struct {
+	// Nuclear charge (Z=1 for hydrogen, Z=2 for He+, Z=3 for Li2+, ...).
+	Z int
+	// Principal quantum number.
+	n int
+	// Azimuthal quantum number.
+	l int
+	// Magnetic quantum number.
+	m int
+}
+
+// register registers the flags specifying an orbital with fs.
+func (f *orbitalFlags) register(fs *flag.FlagSet) {
+	fs.IntVar(&f.Z, "Z", 1, "nuclear charge (Z=1 for hydrogen, Z=2 for He+, ...)")
+	fs.IntVar(&f.n, "n", 1, "principal quantum number (if no orbitals are given)")
+	fs.IntVar(&f.l, "l", 0, "azimuthal quantum number (if no orbitals are given)")
+	fs.IntVar(&f.m, "m", 0, "magnetic quantum number (if no orbitals are given)")
+}
+
+// specs returns the orbital specifications of the given command line arguments
+// (e.g. "3d_z2", "4f-3", "sp3" or "n=3,l=2,m=-1"), or the orbital specified by
+// the -n, -l and -m flags in the given basis if no arguments are given.
+func (f *orbitalFlags) specs(args []string, basis wave.Basis) ([]wave.Spec, error) {
+	if !(f.Z >= 1) {
+		return nil, errors.Errorf("invalid Z; expected Z >= 1, got %d", f.Z)
+	}
+	if len(args) == 0 {
+		if err := wave.CheckQuantumNumbers(f.Z, f.n, f.l, f.m); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		spec := wave.Spec{N: f.n, L: f.l, M: f.m, Basis: basis}
+		return []wave.Spec{spec}, nil
+	}
+	var specs []wave.Spec
+	for _, arg := range args {
+		spec, err := wave.ParseSpec(arg)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// newFlagSet returns a new flag set of the given command, with a usage message
+// describing the command and its arguments.
+func newFlagSet(cmd, args, desc string) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "%s\n\nUsage:\n\n\torbitals %s [flags]%s\n\nFlags:\n\n", desc, cmd, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// Description of orbital arguments, as used in usage messages of commands.
+const orbitalArgsDesc = `
+
+Orbitals are specified in spectroscopic notation (e.g. 3d_z2, 2p_x or 4f-3), by
+quantum numbers (e.g. n=3,l=2,m=-1), or by hybridization (e.g. sp3, sp3d2 or
+dsp2).`
+
+// modelCmd generates 3D-models of orbitals, as specified by the command line
+// arguments.
+func modelCmd(args []string) error {
+	fs := newFlagSet("model", " [orbital...]", "Generate 3D-models of orbitals."+orbitalArgsDesc)
+	var (
+		orbital orbitalFlags
+		model   modelFlags
+		all     bool
+	)
+	orbital.register(fs)
+	model.register(fs)
+	fs.BoolVar(&all, "all", false, "generate 3D-models of the 1s-, 2s-, 3s-, 2p-, 3p- and 3d-orbitals")
+	if err := fs.Parse(args); err != nil {
+		return errors.WithStack(err)
+	}
+	conf, err := model.config()
+	if err != nil {
+		return invalidInput(err)
+	}
+	specs, err := orbital.specs(fs.Args(), conf.basis)
+	if err != nil {
+		return invalidInput(err)
+	}
+	if all {
+		if fs.NArg() > 0 {
+			return invalidInput(errors.Errorf("unexpected orbitals %q with -all flag", fs.Args()))
+		}
+		return genModels(conf, orbital.Z)
+	}
+	for _, spec := range specs {
+		if err := genSpecModels(conf, orbital.Z, spec); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// hybridCmd generates 3D-models of hybrid orbitals, as specified by the command
+// line arguments.
+func hybridCmd(args []string) error {
+	fs := newFlagSet("hybrid", "", "Generate 3D-models of hybrid orbitals.")
+	var (
+		model  modelFlags
+		Z      int
+		hybrid string
+		bonds  string
+	)
+	model.register(fs)
+	fs.IntVar(&Z, "Z", 1, "nuclear charge (Z=1 for hydrogen, Z=2 for He+, ...)")
+	fs.StringVar(&hybrid, "type", "all", "hybridization (sp, sp2, sp3, sp3d, sp3d2, dsp2 or all)")
+	fs.StringVar(&bonds, "bonds", "", `semicolon-separated bond vectors of sp hybrid orbitals (e.g. "0.79,0.61,0;-0.79,0.61,0" for water); overrides -type`)
+	if err := fs.Parse(args); err != nil {
+		return errors.WithStack(err)
+	}
+	if fs.NArg() > 0 {
+		return invalidInput(errors.Errorf("unexpected arguments to hybrid command: %q", fs.Args()))
+	}
+	if !(Z >= 1) {
+		return invalidInput(errors.Errorf("invalid Z; expected Z >= 1, got %d", Z))
+	}
+	if len(bonds) > 0 {
+		vs, err := parseBonds(bonds)
+		if err != nil {
+			return invalidInput(err)
+		}
+		conf, err := model.config()
+		if err != nil {
+			return invalidInput(err)
+		}
+		return genBondModels(conf, Z, vs)
+	}
+	var specs []wave.Spec
+	if hybrid == "all" {
+		for _, name := range wave.Hybridizations() {
+			specs = append(specs, wave.Spec{Hybrid: name})
+		}
+	} else {
+		spec, err := wave.ParseSpec(hybrid)
+		if err != nil {
+			return invalidInput(err)
+		}
+		if !spec.IsHybrid() {
+			return invalidInput(errors.Errorf("invalid hybridization %q; expected one of %s", hybrid, strings.Join(wave.Hybridizations(), ", ")))
+		}
+		specs = append(specs, spec)
+	}
+	conf, err := model.config()
+	if err != nil {
+		return invalidInput(err)
+	}
+	for _, spec := range specs {
+		if err := genSpecModels(conf, Z, spec); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// plotCmd generates a plot of the radial probability of orbitals, as specified
+// by the command line arguments.
+func plotCmd(args []string) error {
+	fs := newFlagSet("plot", " [orbital...]", "Generate plot of radial probability of orbitals."+orbitalArgsDesc)
+	var (
+		orbital orbitalFlags
+		all     bool
+		outDir  string
+	)
+	orbital.register(fs)
+	fs.BoolVar(&all, "all", false, "plot the 1s-, 2s-, 3s-, 2p-, 3p- and 3d-orbitals")
+	fs.StringVar(&outDir, "o", ".", "output directory")
+	if err := fs.Parse(args); err != nil {
+		return errors.WithStack(err)
+	}
+	specs, err := orbital.specs(fs.Args(), wave.ComplexBasis)
+	if err != nil {
+		return invalidInput(err)
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	Z := orbital.Z
+	if all {
+		if fs.NArg() > 0 {
+			return invalidInput(errors.Errorf("unexpected orbitals %q with -all flag", fs.Args()))
+		}
+		dstPath := filepath.Join(outDir, fmt.Sprintf("radial_probability%s.png", getChargeSuffix(Z)))
+		lines, err := getLines(Z)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		return genPlot(dstPath, lines...)
+	}
+	var lines []Line
+	for _, spec := range specs {
+		if spec.IsHybrid() {
+			return invalidInput(errors.Errorf("support for radial probability plot of %s hybrid orbitals not yet implemented", spec))
+		}
+		line, err := getLine(Z, spec.N, spec.L, spec.M)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		lines = append(lines, line)
+	}
+	name := fmt.Sprintf("radial_probability%s", getChargeSuffix(Z))
+	if len(specs) == 1 {
+		name += "_" + specs[0].String()
+	}
+	dstPath := filepath.Join(outDir, name+".png")
+	return genPlot(dstPath, lines...)
+}
+
+// infoCmd prints information about orbitals, as specified by the command line
+// arguments.
+func infoCmd(args []string) error {
+	fs := newFlagSet("info", " [orbital...]", "Print information about orbitals."+orbitalArgsDesc)
+	var orbital orbitalFlags
+	orbital.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return errors.WithStack(err)
+	}
+	specs, err := orbital.specs(fs.Args(), wave.RealBasis)
+	if err != nil {
+		return invalidInput(err)
+	}
+	Z := orbital.Z
+	for i, spec := range specs {
+		if i > 0 {
+			fmt.Println()
+		}
+		if spec.IsHybrid() {
+			hs, err := wave.Hybrids(spec.Hybrid, Z)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			fmt.Printf("hybrid:         %s (%d orbitals)\n", spec, len(hs))
+			fmt.Printf("nuclear charge: Z=%d\n", Z)
+			fmt.Printf("energy:         %.4f eV\n", hs[0].Energy()/wave.ElectronVolt)
+			continue
+		}
+		w, err := wave.NewHydrogenic(spec.Basis, Z, spec.N, spec.L, spec.M)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		n, l, m := spec.N, spec.L, spec.M
+		// Expectation value of the radius; <r> = a_0/(2Z) (3n^2 - l(l+1)).
+		meanRadius := wave.BohrRadius / (2 * float64(Z)) * float64(3*n*n-l*(l+1))
+		fmt.Printf("orbital:        %s (n=%d, l=%d, m=%d)\n", spec, n, l, m)
+		fmt.Printf("nuclear charge: Z=%d\n", Z)
+		fmt.Printf("energy:         %.4f eV\n", w.Energy()/wave.ElectronVolt)
+		fmt.Printf("radial nodes:   %d\n", n-l-1)
+		fmt.Printf("angular nodes:  %d\n", l)
+		fmt.Printf("mean radius:    %.1f pm\n", meanRadius/pm)
+	}
+	return nil
+}
+
+// inputError is an error caused by invalid command line input (e.g. invalid
+// flags, orbital specifications or bond vectors). Input errors are reported
+// without stack traces.
+type inputError struct {
+	err error
+}
+
+// Error returns the error message of the input error.
+func (e *inputError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error of the input error.
+func (e *inputError) Unwrap() error {
+	return e.err
+}
+
+// invalidInput marks the given error as caused by invalid command line input.
+func invalidInput(err error) error {
+	return errors.WithStack(&inputError{err: err})
+}
+
+// isInputError reports whether the given error is caused by invalid command
+// line input, or by invalid or unsupported quantum numbers.
+func isInputError(err error) bool {
+	var (
+		e   *inputError
+		qe  *wave.InvalidQuantumNumberError
+		uqe *wave.UnsupportedQuantumNumberError
+	)
+	return errors.As(err, &e) || errors.As(err, &qe) || errors.As(err, &uqe)
+}
+
+// parseBonds returns the bond vectors of the given semicolon-separated list of
+// comma-separated (x, y, z)-vectors (e.g. "0.79,0.61,0;-0.79,0.61,0").
+func parseBonds(s string) ([][3]float64, error) {
+	var bonds [][3]float64
+	for _, v := range strings.Split(s, ";") {
+		xs, err := parseFloats(v, 3)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid bond vector %q", v)
+		}
+		bonds = append(bonds, [3]float64{xs[0], xs[1], xs[2]})
+	}
+	return bonds, nil
+}
+
+// parseRotation returns the rotation of the given comma-separated Euler angles
+// in degrees or row-major rotation matrix, or nil if both are empty.
+func parseRotation(euler, matrix string) (*wave.Rotation, error) {
+	switch {
+	case len(euler) > 0 && len(matrix) > 0:
+		return nil, errors.New("invalid rotation; expected either Euler angles or rotation matrix, got both")
+	case len(euler) > 0:
+		xs, err := parseFloats(euler, 3)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid Euler angles %q", euler)
+		}
+		rot := wave.EulerRotation(xs[0]*math.Pi/180, xs[1]*math.Pi/180, xs[2]*math.Pi/180)
+		return &rot, nil
+	case len(matrix) > 0:
+		xs, err := parseFloats(matrix, 9)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rotation matrix %q", matrix)
+		}
+		var m [3][3]float64
+		for i, x := range xs {
+			m[i/3][i%3] = x
+		}
+		rot, err := wave.NewRotation(m)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return &rot, nil
+	}
+	return nil, nil
+}
+
+// parseFloats parses the given comma-separated list of n floating-point
+// numbers.
+func parseFloats(s string, n int) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, errors.Errorf("expected %d comma-separated numbers, got %d", n, len(parts))
+	}
+	var xs []float64
+	for _, part := range parts {
+		x, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		xs = append(xs, x)
+	}
+	return xs, nil
+}
+
+// parseSampler returns the sampler of the given name.
+func parseSampler(s string) (Sampler, error) {
+	for _, sampler := range []Sampler{CartesianSampler, SphericSampler, RejectionSampler, MetropolisSampler, MeshSampler} {
+		if s == sampler.String() {
+			return sampler, nil
+		}
+	}
+	return 0, errors.Errorf("invalid sampler; expected cartesian, spheric, rejection, metropolis or mesh, got %q", s)
+}
+
+// parseMode returns the sampling mode of the given name.
+func parseMode(s string) (sample.Mode, error) {
+	for _, mode := range []sample.Mode{sample.DensityMode, sample.RadialMode, sample.SignedMode} {
+		if s == mode.String() {
+			return mode, nil
+		}
+	}
+	return 0, errors.Errorf("invalid sampling mode; expected density, radial or signed, got %q", s)
+}
+
+// parseSphere returns the sampling scheme of directions of the given name.
+func parseSphere(s string) (sample.Sphere, error) {
+	for _, sphere := range []sample.Sphere{sample.UniformSphere, sample.FibonacciSphere, sample.HEALPixSphere} {
+		if s == sphere.String() {
+			return sphere, nil
+		}
+	}
+	return 0, errors.Errorf("invalid sampling scheme of directions; expected uniform, fibonacci or healpix, got %q", s)
+}
+
+// parseBasis returns the basis of the given name.
+func parseBasis(s string) (wave.Basis, error) {
+	switch s {
+	case "complex":
+		return wave.ComplexBasis, nil
+	case "real":
+		return wave.RealBasis, nil
+	}
+	return 0, errors.Errorf("invalid basis; expected real or complex, got %q", s)
+}
+
+// parsePruneStrategy returns the pruning strategy of the given name and
+// threshold. A threshold of 0 selects the default threshold of the strategy.
+func parsePruneStrategy(s string, threshold float64) (prune.Strategy, error) {
+	switch s {
+	case "abs":
+		if threshold == 0 {
+			threshold = 1.0e-11
+		}
+		return prune.Abs(threshold), nil
+	case "mass":
+		if threshold == 0 {
+			threshold = 0.99
+		}
+		if !(0 < threshold && threshold <= 1) {
+			return nil, errors.Errorf("invalid threshold of mass pruning; expected 0 < threshold <= 1, got %g", threshold)
+		}
+		return prune.Mass(threshold), nil
+	case "topk":
+		if threshold == 0 {
+			threshold = 500000
+		}
+		if !(threshold >= 1 && threshold == math.Trunc(threshold)) {
+			return nil, errors.Errorf("invalid threshold of topk pruning; expected positive integer, got %g", threshold)
+		}
+		return prune.TopK(int(threshold)), nil
+	case "rel":
+		if threshold == 0 {
+			threshold = 1.0e-4
+		}
+		return prune.Rel(threshold), nil
+	}
+	return nil, errors.Errorf("invalid pruning strategy; expected abs, mass, topk or rel, got %q", s)
+}
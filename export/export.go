@@ -0,0 +1,251 @@
+// Package export implements export of 3D-models of electron orbitals to OBJ and
+// JSON files.
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mewmew/orbitals/mesh"
+	"github.com/mewmew/orbitals/orb"
+	"github.com/pkg/errors"
+)
+
+// PointWriter writes the points of a 3D-model to a file, one point at a time,
+// so that 3D-models may be written while being generated.
+type PointWriter interface {
+	// WritePoint writes the given point.
+	WritePoint(p orb.CartesianPoint) error
+	// Close flushes the written points and closes the file.
+	Close() error
+}
+
+// pointFile is a file of points, written by a point writing function.
+type pointFile struct {
+	f  *os.File
+	bw *bufio.Writer
+	// Writes the given point to bw.
+	write func(p orb.CartesianPoint) error
+}
+
+// createPointFile creates a file of points at dstPath.
+func createPointFile(dstPath string) (*pointFile, error) {
+	f, err := os.Create(dstPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	pf := &pointFile{
+		f:  f,
+		bw: bufio.NewWriter(f),
+	}
+	return pf, nil
+}
+
+// WritePoint writes the given point.
+func (pf *pointFile) WritePoint(p orb.CartesianPoint) error {
+	return pf.write(p)
+}
+
+// Close flushes the written points and closes the file.
+func (pf *pointFile) Close() error {
+	if err := pf.bw.Flush(); err != nil {
+		pf.f.Close()
+		return errors.WithStack(err)
+	}
+	if err := pf.f.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// CreateJSONFile creates a JSON file at dstPath, to which points are written in
+// JSON format, one per line. The points are preceded by the metadata md, if
+// non-nil.
+func CreateJSONFile(dstPath string, md *Metadata) (PointWriter, error) {
+	pf, err := createPointFile(dstPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	enc := json.NewEncoder(pf.bw)
+	if err := md.writeJSON(enc); err != nil {
+		pf.Close()
+		return nil, errors.WithStack(err)
+	}
+	pf.write = func(p orb.CartesianPoint) error {
+		if err := enc.Encode(p); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	return pf, nil
+}
+
+// CreateObjFile creates an OBJ file at dstPath, to which points are written as
+// vertices (see WriteObjFile), with per-vertex colours based on the sign of psi
+// if signed is set (see WriteSignedObjFile). The points are preceded by the
+// metadata md as comments, if non-nil.
+func CreateObjFile(dstPath string, md *Metadata, signed bool) (PointWriter, error) {
+	pf, err := createPointFile(dstPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := md.writeObjComments(pf.bw); err != nil {
+		pf.Close()
+		return nil, errors.WithStack(err)
+	}
+	pf.write = func(p orb.CartesianPoint) error {
+		if !signed {
+			// TODO: Also include probablility? Perhaps as colour or transparency?
+			if _, err := fmt.Fprintf(pf.bw, "v %.6g %.6g %.6g\n", p.X, p.Y, p.Z); err != nil {
+				return errors.WithStack(err)
+			}
+			return nil
+		}
+		c := PositiveColor
+		if p.Signed() < 0 {
+			c = NegativeColor
+		}
+		if _, err := fmt.Fprintf(pf.bw, "v %.6g %.6g %.6g %.3f %.3f %.3f\n", p.X, p.Y, p.Z, c[0], c[1], c[2]); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	return pf, nil
+}
+
+// writePoints writes the points to w and closes w.
+func writePoints(w PointWriter, ps []orb.CartesianPoint) error {
+	for _, p := range ps {
+		if err := w.WritePoint(p); err != nil {
+			w.Close()
+			return errors.WithStack(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// WriteJSONFile marshals ps into JSON format, writing to dstPath. The points
+// are preceded by the metadata md, if non-nil.
+func WriteJSONFile(dstPath string, md *Metadata, ps []orb.CartesianPoint) error {
+	w, err := CreateJSONFile(dstPath, md)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return writePoints(w, ps)
+}
+
+// WriteObjFile stores the points in OBJ format, preceded by the metadata md as
+// comments, if non-nil.
+//
+// Example file:
+//
+//    # orbital: 1s
+//    v 2.00000 0.00000 0.00000
+//    v 2.00000 1.00000 0.00000
+//    v 1.99037 0.00000 0.19603
+func WriteObjFile(dstPath string, md *Metadata, ps []orb.CartesianPoint) error {
+	w, err := CreateObjFile(dstPath, md, false)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return writePoints(w, ps)
+}
+
+// Vertex colours of positive and negative lobes.
+var (
+	// Blue.
+	PositiveColor = [3]float64{0.0, 0.0, 1.0}
+	// Red.
+	NegativeColor = [3]float64{1.0, 0.0, 0.0}
+)
+
+// WriteSignedObjFile stores the points in OBJ format, with per-vertex colours
+// based on the sign of psi; PositiveColor for positive lobes and NegativeColor
+// for negative lobes. The metadata md is written as comments, if non-nil.
+//
+// Example file:
+//
+//    v 52.9177 0 -13.2294 0.000 0.000 1.000
+//    v -52.9177 0 -13.2294 1.000 0.000 0.000
+func WriteSignedObjFile(dstPath string, md *Metadata, ps []orb.CartesianPoint) error {
+	w, err := CreateObjFile(dstPath, md, true)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return writePoints(w, ps)
+}
+
+// WriteMeshObjFile stores the isosurface meshes of the positive and negative
+// lobes in OBJ format, as separate objects with vertex normals. The vertices
+// are coloured by lobe sign; PositiveColor and NegativeColor, respectively. The
+// metadata md is written as comments, if non-nil.
+//
+// Example file:
+//
+//    o positive
+//    v 140.812 0 0 0.000 0.000 1.000
+//    vn 1.000 0.000 0.000
+//    f 1//1 2//2 3//3
+func WriteMeshObjFile(dstPath string, md *Metadata, positive, negative *mesh.Mesh) error {
+	pf, err := createPointFile(dstPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := writeMeshObj(pf.bw, md, positive, negative); err != nil {
+		pf.Close()
+		return errors.WithStack(err)
+	}
+	if err := pf.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// writeMeshObj writes the isosurface meshes in OBJ format to bw (see
+// WriteMeshObjFile).
+func writeMeshObj(bw *bufio.Writer, md *Metadata, positive, negative *mesh.Mesh) error {
+	if err := md.writeObjComments(bw); err != nil {
+		return errors.WithStack(err)
+	}
+	// OBJ indices are 1-based and global to the file.
+	offset := 1
+	objs := []struct {
+		name  string
+		m     *mesh.Mesh
+		color [3]float64
+	}{
+		{name: "positive", m: positive, color: PositiveColor},
+		{name: "negative", m: negative, color: NegativeColor},
+	}
+	for _, obj := range objs {
+		if len(obj.m.Faces) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(bw, "o %s\n", obj.name); err != nil {
+			return errors.WithStack(err)
+		}
+		for _, v := range obj.m.Vertices {
+			if _, err := fmt.Fprintf(bw, "v %.6g %.6g %.6g %.3f %.3f %.3f\n", v[0], v[1], v[2], obj.color[0], obj.color[1], obj.color[2]); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		for _, vn := range obj.m.Normals {
+			if _, err := fmt.Fprintf(bw, "vn %.3f %.3f %.3f\n", vn[0], vn[1], vn[2]); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		for _, face := range obj.m.Faces {
+			a, b, c := face[0]+offset, face[1]+offset, face[2]+offset
+			if _, err := fmt.Fprintf(bw, "f %d//%d %d//%d %d//%d\n", a, a, b, b, c, c); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		offset += len(obj.m.Vertices)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package export
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mewmew/orbitals/mesh"
+	"github.com/mewmew/orbitals/sample"
+	"github.com/mewmew/orbitals/wave"
+)
+
+func TestWriteObjFileUnique(t *testing.T) {
+	// The auto-scaled extent of Z=100 has a grid step well below one picometer.
+	w, err := wave.NewHydrogenic(wave.RealBasis, 100, 2, 1, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ext, err := sample.ExtentOf(w)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ext.Grid = 41
+	pts, err := sample.Cartesian(sample.SignedMode, ext, w.Psi)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	dir := t.TempDir()
+	pointsPath := filepath.Join(dir, "points.obj")
+	if err := WriteSignedObjFile(pointsPath, NewMetadata(w), pts); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	meshPath := filepath.Join(dir, "mesh.obj")
+	// Iso-value of the probability density between the sampled grid values; at
+	// iso-values sampled on a grid point (as by mesh.Enclosing), the vertices of
+	// the cube edges meeting at the grid point coincide.
+	_, _, iso, err := mesh.Enclosing(ext, w.Psi, 0.9)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	positive, negative, err := mesh.FromPsi(ext, w.Psi, 1.05*iso)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if err := WriteMeshObjFile(meshPath, NewMetadata(w), positive, negative); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, path := range []string{pointsPath, meshPath} {
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		n := 0
+		seen := make(map[string]bool)
+		for _, line := range strings.Split(string(buf), "\n") {
+			if !strings.HasPrefix(line, "v ") {
+				continue
+			}
+			// Vertex position, without colour.
+			fields := strings.Fields(line)
+			seen[strings.Join(fields[1:4], " ")] = true
+			n++
+		}
+		if n == 0 || len(seen) != n {
+			t.Errorf("%s: duplicate vertices; expected %d unique vertices, got %d", filepath.Base(path), n, len(seen))
+		}
+	}
+}
+
+func TestWriteSignedObjFileColor(t *testing.T) {
+	golden := []struct {
+		Z, n, l, m int
+	}{
+		{Z: 1, n: 2, l: 1, m: 0},  // 2p_z
+		{Z: 1, n: 3, l: 2, m: -2}, // 3d_xy
+		{Z: 2, n: 3, l: 0, m: 0},  // 3s
+	}
+	for _, g := range golden {
+		w, err := wave.NewHydrogenic(wave.RealBasis, g.Z, g.n, g.l, g.m)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext, err := sample.ExtentOf(w)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext.Grid = 21
+		pts, err := sample.Cartesian(sample.SignedMode, ext, w.Psi)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		path := filepath.Join(t.TempDir(), "points.obj")
+		if err := WriteSignedObjFile(path, NewMetadata(w), pts); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		npos, nneg := 0, 0
+		for _, line := range strings.Split(string(buf), "\n") {
+			if !strings.HasPrefix(line, "v ") {
+				continue
+			}
+			var x, y, z float64
+			var c [3]float64
+			if _, err := fmt.Sscanf(line, "v %g %g %g %g %g %g", &x, &y, &z, &c[0], &c[1], &c[2]); err != nil {
+				t.Fatalf("%s: unable to parse vertex %q; %v", w.Label(), line, err)
+			}
+			// Sign of psi at the vertex position, converted from picometer; skip
+			// vertices close to nodal surfaces, where rounding of the position may
+			// flip the sign.
+			psi := real(w.Psi(wave.SphericalFromCartesian(x*wave.Picometer, y*wave.Picometer, z*wave.Picometer)))
+			if math.Abs(psi) < 1e-6 {
+				continue
+			}
+			want := PositiveColor
+			if psi < 0 {
+				want = NegativeColor
+				nneg++
+			} else {
+				npos++
+			}
+			if c != want {
+				t.Errorf("%s: colour mismatch of vertex (%g, %g, %g) with psi=%g; expected %v, got %v", w.Label(), x, y, z, psi, want, c)
+				break
+			}
+		}
+		if npos == 0 || nneg == 0 {
+			t.Errorf("%s: expected vertices of both signs, got %d positive and %d negative", w.Label(), npos, nneg)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mewmew/orbitals/wave"
+	"github.com/pkg/errors"
+)
+
+// Metadata describes the wave function of a 3D-model.
+type Metadata struct {
+	// Label of the wave function (e.g. "3d_z2" or "sp3_0").
+	Label string
+	// Nuclear charge.
+	Z int
+	// Quantum numbers; -1 if without definite value.
+	N, L, M int
+	// Energy with unit eV.
+	Energy float64
+	// Norm of the wave function.
+	Norm float64
+}
+
+// NewMetadata returns the metadata of the given wave function.
+func NewMetadata(w wave.Wavefunction) *Metadata {
+	Z, n, l, m := w.QuantumNumbers()
+	return &Metadata{
+		Label:  w.Label(),
+		Z:      Z,
+		N:      n,
+		L:      l,
+		M:      m,
+		Energy: w.Energy() / wave.ElectronVolt,
+		Norm:   w.Norm(),
+	}
+}
+
+// writeObjComments writes the metadata as OBJ comments to w. No comments are
+// written if md is nil.
+//
+// Example output:
+//
+//    # orbital: 3d_z2
+//    # quantum numbers: Z=1 n=3 l=2 m=0
+//    # energy: -1.5117 eV
+//    # norm: 1
+func (md *Metadata) writeObjComments(w io.Writer) error {
+	if md == nil {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "# orbital: %s\n# quantum numbers: Z=%d n=%d l=%d m=%d\n# energy: %.4f eV\n# norm: %g\n", md.Label, md.Z, md.N, md.L, md.M, md.Energy, md.Norm); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// writeJSON writes the metadata as a JSON object with a single "Metadata" key
+// to enc. Nothing is written if md is nil.
+func (md *Metadata) writeJSON(enc *json.Encoder) error {
+	if md == nil {
+		return nil
+	}
+	v := struct{ Metadata *Metadata }{Metadata: md}
+	if err := enc.Encode(v); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
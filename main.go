@@ -2,18 +2,48 @@
 //
 //    [1]: https://chemistrygod.com/atomic-orbital
 
+// The orbitals tool generates 3D-models and plots of electron orbitals.
+//
+// Usage:
+//
+//    orbitals <command> [flags]
+//
+// Commands:
+//
+//    model    generate 3D-models of orbitals
+//    hybrid   generate 3D-models of hybrid orbitals
+//    plot     generate plot of radial probability of orbitals
+//    info     print information about orbitals
+//
+// Examples:
+//
+//    orbitals model -sampler mesh 3d_z2 2p_x
+//    orbitals model -all -Z 2 -o out
+//    orbitals model -sampler metropolis -samples 50000 sp3
+//    orbitals hybrid -bonds "0.7907,0.6122,0;-0.7907,0.6122,0"
+//    orbitals plot -all
+//    orbitals info 4f-2
+//
+// Orbitals are specified in spectroscopic notation (e.g. 3d_z2, 2p_x or 4f-3),
+// by quantum numbers (e.g. n=3,l=2,m=-1 or the -n, -l and -m flags), or by
+// hybridization (e.g. sp3, sp3d2 or dsp2).
 package main
 
 import (
-	"bufio"
-	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"math"
-	"math/cmplx"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
 
+	"github.com/mewmew/orbitals/export"
+	"github.com/mewmew/orbitals/mesh"
 	"github.com/mewmew/orbitals/orb"
+	"github.com/mewmew/orbitals/sample"
+	"github.com/mewmew/orbitals/wave"
 	"github.com/pkg/errors"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
@@ -21,32 +51,46 @@ import (
 	"gonum.org/v1/plot/vg"
 )
 
-// Convert meter to picometer.
-const pm = 0.000000000001 // 1.0 * 10^{-12} m
-
-// Bohr radius with unit m.
-const a0 = 52.9177210903 * pm // 52.9 pm
+// Convert picometer to atomic units of length.
+const pm = wave.Picometer
 
 func main() {
-	// Generate plot of radial probability for the 1s-, 2s-, 3s-, 2p-, 3p- and
-	// 3d-orbitals.
-	//lines := getLines()
-	//if err := genPlot("radial_probability.png", lines...); err != nil {
-	//	log.Fatalf("%+v", err)
-	//}
-
-	// Generate 3D-models visualizing the probability distribution of the 1s-,
-	// 2s-, 3s-, 2p-, 3p- and 3d-orbitals.
-	if err := genModels(); err != nil {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+	var err error
+	switch cmd {
+	case "model":
+		err = modelCmd(args)
+	case "hybrid":
+		err = hybridCmd(args)
+	case "plot":
+		err = plotCmd(args)
+	case "info":
+		err = infoCmd(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", cmd)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		// Report input errors without stack traces.
+		if isInputError(err) {
+			log.Fatalf("%v", err)
+		}
 		log.Fatalf("%+v", err)
 	}
 }
 
 // genModels generates 3D-models visualizing the probability distribution of the
-// 1s-, 2s-, 3s-, 2p-, 3p- and 3d-orbitals.
-func genModels() error {
-	//genModel := genSphericModel
-	genModel := genCartesianModel
+// 1s-, 2s-, 3s-, 2p-, 3p- and 3d-orbitals with nuclear charge Z. The 3D-models
+// are generated concurrently, using conf.workers workers.
+func genModels(conf *config, Z int) error {
+	var jobs []func(conf *config) error
 	// 1s-orbital.
 	{
 		const (
@@ -54,9 +98,7 @@ func genModels() error {
 			l = 0 // azimuthal quantum number
 			m = 0 // magnetic quantum number
 		)
-		if err := genModel(n, l, m); err != nil {
-			return errors.WithStack(err)
-		}
+		jobs = append(jobs, genModelJob(Z, n, l, m))
 	}
 	// 2s-orbital.
 	{
@@ -65,9 +107,7 @@ func genModels() error {
 			l = 0 // azimuthal quantum number
 			m = 0 // magnetic quantum number
 		)
-		if err := genModel(n, l, m); err != nil {
-			return errors.WithStack(err)
-		}
+		jobs = append(jobs, genModelJob(Z, n, l, m))
 	}
 	// 3s-orbital.
 	{
@@ -76,9 +116,7 @@ func genModels() error {
 			l = 0 // azimuthal quantum number
 			m = 0 // magnetic quantum number
 		)
-		if err := genModel(n, l, m); err != nil {
-			return errors.WithStack(err)
-		}
+		jobs = append(jobs, genModelJob(Z, n, l, m))
 	}
 	// 2p-orbitals.
 	{
@@ -88,9 +126,7 @@ func genModels() error {
 			//m = 0 // magnetic quantum number
 		)
 		for m := -l; m <= l; m++ {
-			if err := genModel(n, l, m); err != nil {
-				return errors.WithStack(err)
-			}
+			jobs = append(jobs, genModelJob(Z, n, l, m))
 		}
 	}
 	// 3p-orbitals.
@@ -101,9 +137,7 @@ func genModels() error {
 			//m = 0 // magnetic quantum number
 		)
 		for m := -l; m <= l; m++ {
-			if err := genModel(n, l, m); err != nil {
-				return errors.WithStack(err)
-			}
+			jobs = append(jobs, genModelJob(Z, n, l, m))
 		}
 	}
 	// 3d-orbitals.
@@ -114,94 +148,317 @@ func genModels() error {
 			//m = 0 // magnetic quantum number
 		)
 		for m := -l; m <= l; m++ {
-			if err := genModel(n, l, m); err != nil {
-				return errors.WithStack(err)
-			}
+			jobs = append(jobs, genModelJob(Z, n, l, m))
 		}
 	}
-	// Hybrid orbitals.
-	//
-	// sp
-	for i, Psi := range psiSPHybridOrbitals {
-		dstPath := fmt.Sprintf("hybrid_orbital_sp_%d.obj", i)
-		if err := genCartesianHybridModel(Psi, dstPath); err != nil {
-			return errors.WithStack(err)
-		}
+	return runJobs(conf, jobs)
+}
+
+// genModelJob returns a job generating the 3D-model of the specified (n, l,
+// m)-orbital with nuclear charge Z (see genModel).
+func genModelJob(Z, n, l, m int) func(conf *config) error {
+	return func(conf *config) error {
+		return genModel(conf, Z, n, l, m)
 	}
-	// sp^2
-	for i, Psi := range psiSP2HybridOrbitals {
-		dstPath := fmt.Sprintf("hybrid_orbital_sp^2_%d.obj", i)
-		if err := genCartesianHybridModel(Psi, dstPath); err != nil {
-			return errors.WithStack(err)
-		}
+}
+
+// genWavefunctionJob returns a job generating the 3D-model of the given wave
+// function (see genModelWithWavefunction).
+func genWavefunctionJob(w wave.Wavefunction, name string) func(conf *config) error {
+	return func(conf *config) error {
+		return genModelWithWavefunction(conf, w, name)
 	}
-	// sp^3
-	for i, Psi := range psiSP3HybridOrbitals {
-		dstPath := fmt.Sprintf("hybrid_orbital_sp^3_%d.obj", i)
-		if err := genCartesianHybridModel(Psi, dstPath); err != nil {
-			return errors.WithStack(err)
+}
+
+// runJobs runs the given jobs concurrently, as specified by conf. The
+// conf.workers workers (0 for the number of CPUs) are shared between the jobs
+// and the grid evaluation of each job; up to conf.workers jobs are run
+// concurrently, each with an equal share of the workers for grid evaluation.
+// The first error encountered is returned, after all started jobs have
+// finished; remaining jobs are not started.
+func runJobs(conf *config, jobs []func(conf *config) error) error {
+	total := conf.workers
+	if total <= 0 {
+		total = runtime.GOMAXPROCS(0)
+	}
+	// Number of concurrent jobs.
+	workers := total
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	if workers < 1 {
+		return nil
+	}
+	// Share of workers of each job.
+	c := *conf
+	c.workers = total / workers
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		first error
+	)
+	queue := make(chan func(conf *config) error)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				if err := job(&c); err != nil {
+					mu.Lock()
+					if first == nil {
+						first = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, job := range jobs {
+		mu.Lock()
+		failed := first != nil
+		mu.Unlock()
+		if failed {
+			break
 		}
+		queue <- job
 	}
-	return nil
+	close(queue)
+	wg.Wait()
+	return errors.WithStack(first)
 }
 
-// Probability threshold.
-//const threshold = 1.0e-6
-const threshold = 1.0e-11
-
-// genSphericModel generates a 3D-model visualizing the probability distribution
-// of the specified (n, l, m)-orbital.
-func genSphericModel(n, l, m int) error {
-	pts := getSphericModel(n, l, m)
-	ps := pruneSphericModel(pts, threshold)
-	dstPath := getObjModelName(n, l, m)
-	fmt.Printf("creating %q\n", dstPath)
-	if err := writeObjFile(dstPath, ps); err != nil {
+// genHybridModels generates 3D-models visualizing the probability distribution
+// of the hybrid orbitals of the specified hybridization (e.g. "sp3") with
+// nuclear charge Z.
+func genHybridModels(conf *config, Z int, hybrid string) error {
+	hs, err := wave.Hybrids(hybrid, Z)
+	if err != nil {
 		return errors.WithStack(err)
 	}
-	return nil
+	var jobs []func(conf *config) error
+	for i, h := range hs {
+		name := getHybridModelName(Z, hybrid, i)
+		jobs = append(jobs, genWavefunctionJob(h, name))
+	}
+	return runJobs(conf, jobs)
 }
 
-// genCartesianModel generates a 3D-model visualizing the probability
-// distribution of the specified (n, l, m)-orbital.
-func genCartesianModel(n, l, m int) error {
-	pts := getCartesianModel(n, l, m)
-	ps := pruneCartesianModel(pts, threshold)
-	dstPath := getObjModelName(n, l, m)
-	fmt.Printf("creating %q\n", dstPath)
-	if err := writeObjFile(dstPath, ps); err != nil {
+// genBondModels generates 3D-models visualizing the probability distribution of
+// the sp hybrid orbitals of the 2-shell with nuclear charge Z, pointing along
+// the given bond vectors, and of the lone pair hybrid orbitals completing the
+// set.
+func genBondModels(conf *config, Z int, bonds [][3]float64) error {
+	const n = 2 // principal quantum number
+	hs, err := wave.BondHybrids(Z, n, bonds)
+	if err != nil {
+		// The bond vectors are given on the command line.
+		return invalidInput(err)
+	}
+	var jobs []func(conf *config) error
+	for i, h := range hs {
+		fmt.Printf("%s: %.1f%% s-character\n", h.Label(), 100*wave.SCharacter(h, n))
+		name := getHybridModelName(Z, "bonds", i)
+		jobs = append(jobs, genWavefunctionJob(h, name))
+	}
+	return runJobs(conf, jobs)
+}
+
+// genSpecModels generates 3D-models visualizing the probability distribution of
+// the orbital, or set of hybrid orbitals, of the given orbital specification
+// with nuclear charge Z. The basis of the orbital specification takes
+// precedence over that of conf.
+func genSpecModels(conf *config, Z int, spec wave.Spec) error {
+	if spec.IsHybrid() {
+		return genHybridModels(conf, Z, spec.Hybrid)
+	}
+	c := *conf
+	c.basis = spec.Basis
+	return genModel(&c, Z, spec.N, spec.L, spec.M)
+}
+
+// genModel generates a 3D-model visualizing the probability distribution of the
+// specified (n, l, m)-orbital with nuclear charge Z, as specified by conf.
+func genModel(conf *config, Z, n, l, m int) error {
+	w, err := wave.NewHydrogenic(conf.basis, Z, n, l, m)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	name := getModelName(conf.basis, Z, n, l, m)
+	if err := genModelWithWavefunction(conf, w, name); err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
-// genCartesianHybridModel generates a 3D-model visualizing the probability
-// distribution of the specified hybrid wave function psi.
-func genCartesianHybridModel(Psi func(rho, theta, phi float64) float64, dstPath string) error {
-	pts := getCartesianModelWithPsi(Psi)
-	ps := pruneCartesianModel(pts, threshold)
+// genModelWithWavefunction generates a 3D-model visualizing the probability
+// distribution of the specified wave function, as specified by conf. The wave
+// function is rotated by the rotation of conf, if any. The output file is named
+// after the given name (without extension), suffixed by the rotation if any
+// (see getRotationSuffix), and stored in the output directory of conf, together
+// with the metadata of the wave function.
+//
+// The sampled region and resolution are given by modelExtent, and the grid
+// samplers evaluate the wave function through tables of its separable terms
+// (see wave.Tabulate), if possible.
+//
+// The mesh sampler generates isosurfaces enclosing the fraction conf.enclosed
+// of the probability, and the Monte Carlo samplers draw electron positions
+// distributed according to |psi|^2, regardless of mode.
+func genModelWithWavefunction(conf *config, w wave.Wavefunction, name string) error {
+	if conf.rotation != nil {
+		rotated, err := wave.Rotate(w, *conf.rotation)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		w = rotated
+		name += getRotationSuffix(*conf.rotation)
+	}
+	ext, err := modelExtent(conf, w)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	Psi := wave.ComplexPsiFunc(w.Psi)
+	Ray := wave.Rays(Psi)
+	// Evaluate the grids of grid samplers through tables of the separable terms
+	// of the wave function.
+	var rmax float64
+	switch conf.sampler {
+	case CartesianSampler, MeshSampler:
+		rmax = math.Sqrt(3) * ext.Max
+	case SphericSampler:
+		rmax = ext.Max
+	}
+	if rmax > 0 {
+		if tab, err := wave.Tabulate(w, rmax); err == nil {
+			Psi, Ray = tab.Psi, tab.Ray
+		}
+	}
+	var md *export.Metadata
+	if conf.metadata {
+		md = export.NewMetadata(w)
+	}
+	dstPath := filepath.Join(conf.outDir, name+"."+conf.format)
+	var ps []orb.CartesianPoint
+	switch conf.sampler {
+	case CartesianSampler, SphericSampler:
+		// Stream points of grid samplers to the output file, without storing the
+		// points of the full grid.
+		fmt.Printf("creating %q\n", dstPath)
+		pw, err := createModelFile(conf, dstPath, md)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if conf.sampler == SphericSampler {
+			err = sample.SphericStream(conf.mode, ext, Ray, conf.prune, pw.WritePoint)
+		} else {
+			err = sample.CartesianStream(conf.mode, ext, Psi, conf.prune, pw.WritePoint)
+		}
+		if err != nil {
+			pw.Close()
+			return errors.WithStack(err)
+		}
+		if err := pw.Close(); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	case RejectionSampler:
+		ps, err = sample.Rejection(ext, Psi, conf.samples, conf.seed)
+	case MetropolisSampler:
+		ps, err = sample.Metropolis(ext, Psi, conf.samples, conf.seed)
+	case MeshSampler:
+		if conf.format != "obj" {
+			return errors.Errorf("support for %s output of %v sampler not yet implemented", conf.format, conf.sampler)
+		}
+		positive, negative, iso, err := mesh.Enclosing(ext, Psi, conf.enclosed)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		fmt.Printf("creating %q (%g%% boundary surface, iso-value %.3g)\n", dstPath, 100*conf.enclosed, iso)
+		if err := export.WriteMeshObjFile(dstPath, md, positive, negative); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	default:
+		return errors.Errorf("support for sampler %v not yet implemented", conf.sampler)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
 	fmt.Printf("creating %q\n", dstPath)
-	if err := writeObjFile(dstPath, ps); err != nil {
+	if err := writeModelFile(conf, dstPath, md, ps); err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
-// getObjModelName returns a OBJ output file name for the specified (n, l,
-// m)-orbital.
-func getObjModelName(n, l, m int) string {
-	return fmt.Sprintf("orbital_n_%d_l_%d_m_%d.obj", n, l, m)
+// modelExtent returns the sampled region and resolution of the 3D-model of the
+// given wave function, as specified by conf. By default, the sampled region
+// encloses the fraction sample.DefaultEnclosed of the probability (see
+// sample.ExtentOf).
+func modelExtent(conf *config, w wave.Wavefunction) (sample.Extent, error) {
+	ext := sample.Extent{
+		Max:        conf.extent,
+		Grid:       conf.grid,
+		Points:     conf.points,
+		Sphere:     conf.sphere,
+		Directions: conf.directions,
+		Workers:    conf.workers,
+	}
+	if ext.Max == 0 {
+		def, err := sample.ExtentOf(w)
+		if err != nil {
+			return sample.Extent{}, errors.WithStack(err)
+		}
+		ext.Max = def.Max
+	}
+	return ext, nil
 }
 
-// getJsonModelName returns a JSON output file name for the specified (n, l,
-// m)-orbital.
-func getJsonModelName(n, l, m int) string {
-	return fmt.Sprintf("orbital_n_%d_l_%d_m_%d.json", n, l, m)
+// getModelName returns the output file name, without extension, of the
+// specified (n, l, m)-orbital with nuclear charge Z in the given basis. Orbitals
+// are named by their canonical orbital specification; real orbitals after their
+// angular dependence (e.g. "orbital_3d_xy") and complex orbitals after their
+// magnetic quantum number (e.g. "orbital_4f-3").
+func getModelName(basis wave.Basis, Z, n, l, m int) string {
+	spec := wave.Spec{N: n, L: l, M: m, Basis: basis}
+	return fmt.Sprintf("orbital%s_%s", getChargeSuffix(Z), spec)
+}
+
+// getHybridModelName returns the output file name, without extension, of the
+// i:th hybrid orbital of the specified hybridization (e.g. "sp3") with nuclear
+// charge Z.
+func getHybridModelName(Z int, hybrid string, i int) string {
+	return fmt.Sprintf("hybrid_orbital%s_%s_%d", getChargeSuffix(Z), hybrid, i)
+}
+
+// getRotationSuffix returns the file name suffix of the given rotation, by its
+// Euler angles in degrees (e.g. "_euler_0_90_0"); to keep the output files of
+// differently rotated orbitals apart. The Euler angles are those of
+// wave.Rotation.EulerAngles, and are thus the same for equal rotations
+// specified differently.
+func getRotationSuffix(rot wave.Rotation) string {
+	alpha, beta, gamma := rot.EulerAngles()
+	// deg returns the angle in degrees, rounded to 3 decimals.
+	deg := func(a float64) float64 {
+		// Add 0 to turn -0 into 0.
+		return math.Round(a*180/math.Pi*1000)/1000 + 0
+	}
+	return fmt.Sprintf("_euler_%g_%g_%g", deg(alpha), deg(beta), deg(gamma))
+}
+
+// getChargeSuffix returns the file name suffix of the nuclear charge Z. The
+// suffix is empty for hydrogen (Z=1), to keep the output file names of hydrogen
+// orbitals unchanged.
+func getChargeSuffix(Z int) string {
+	if Z == 1 {
+		return ""
+	}
+	return fmt.Sprintf("_Z_%d", Z)
 }
 
 // getLines returns plotter lines for the 1s-, 2s-, 3s-, 2p-, 3p- and
-// 3d-orbitals.
-func getLines() []Line {
+// 3d-orbitals with nuclear charge Z.
+func getLines(Z int) ([]Line, error) {
 	// 1s-orbital.
 	var lines []Line
 	{
@@ -210,7 +467,10 @@ func getLines() []Line {
 			l = 0 // azimuthal quantum number
 			m = 0 // magnetic quantum number
 		)
-		line := getLine(n, l, m)
+		line, err := getLine(Z, n, l, m)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		lines = append(lines, line)
 	}
 	// 2s-orbital.
@@ -220,7 +480,10 @@ func getLines() []Line {
 			l = 0 // azimuthal quantum number
 			m = 0 // magnetic quantum number
 		)
-		line := getLine(n, l, m)
+		line, err := getLine(Z, n, l, m)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		lines = append(lines, line)
 	}
 	// 3s-orbital.
@@ -230,7 +493,10 @@ func getLines() []Line {
 			l = 0 // azimuthal quantum number
 			m = 0 // magnetic quantum number
 		)
-		line := getLine(n, l, m)
+		line, err := getLine(Z, n, l, m)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		lines = append(lines, line)
 	}
 	// 2p-orbitals.
@@ -241,7 +507,10 @@ func getLines() []Line {
 			//m = 0 // magnetic quantum number
 		)
 		for m := -l; m <= l; m++ {
-			line := getLine(n, l, m)
+			line, err := getLine(Z, n, l, m)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
 			lines = append(lines, line)
 		}
 	}
@@ -253,7 +522,10 @@ func getLines() []Line {
 			//m = 0 // magnetic quantum number
 		)
 		for m := -l; m <= l; m++ {
-			line := getLine(n, l, m)
+			line, err := getLine(Z, n, l, m)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
 			lines = append(lines, line)
 		}
 	}
@@ -265,53 +537,54 @@ func getLines() []Line {
 			//m = 0 // magnetic quantum number
 		)
 		for m := -l; m <= l; m++ {
-			line := getLine(n, l, m)
+			line, err := getLine(Z, n, l, m)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
 			lines = append(lines, line)
 		}
 	}
-	return lines
+	return lines, nil
 }
 
-// getLine returns a plotter line of the specified (n, l, m)-orbital.
-func getLine(n, l, m int) Line {
-	vals := getValues(n, l, m)
-	legend := getLegend(n, l, m)
+// getLine returns a plotter line of the specified (n, l, m)-orbital with nuclear
+// charge Z.
+func getLine(Z, n, l, m int) (Line, error) {
+	vals, err := getValues(Z, n, l, m)
+	if err != nil {
+		return Line{}, errors.WithStack(err)
+	}
+	legend := getLegend(Z, n, l, m)
 	line := Line{
 		XYs:    vals,
 		Legend: legend,
 	}
-	return line
+	return line, nil
 }
 
 // getLegend returns a legend for the plotter line of the specified (n, l,
-// m)-orbital.
-func getLegend(n, l, m int) string {
+// m)-orbital with nuclear charge Z.
+func getLegend(Z, n, l, m int) string {
+	if Z != 1 {
+		return fmt.Sprintf("(n=%d, l=%d, m=%d)-orbital (Z=%d)", n, l, m, Z)
+	}
 	return fmt.Sprintf("(n=%d, l=%d, m=%d)-orbital", n, l, m)
 }
 
 // getValues returns the radial probability values of the (n, l, m)-orbital
-// based on the specified principal quantum number, n, azimuthal quantum number,
-// l, and magnetic quantum number, m.
-func getValues(n, l, m int) plotter.XYs {
-	Psi := Orbitals(n, l, m)
+// based on the specified nuclear charge, Z, principal quantum number, n,
+// azimuthal quantum number, l, and magnetic quantum number, m.
+func getValues(Z, n, l, m int) (plotter.XYs, error) {
+	w, err := wave.NewHydrogenic(wave.ComplexBasis, Z, n, l, m)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	var xys plotter.XYs
-	const (
-		//rho = a0   // radial distance (radius)
-		theta = 15 // inclination (angular)
-		phi   = 30 // azimuth (angular)
-	)
-	for r := 0.0 * pm; r < 1300*pm; r += 1.0 * pm {
-		rho := r
-		psi := Psi(rho, theta, phi)
-		//psi2 := math.Pow(psi, 2)
-		radialProb := RadialProb(rho, psi)
-		//fmt.Printf("r=%.2g pm\n", rho/pm)
-		//fmt.Printf("   psi^2:       %v\n", psi2)
-		//fmt.Printf("   radial prob: %v\n", radialProb)
-		//fmt.Println()
+	// The extent of the orbital scales with 1/Z.
+	for r := 0.0 * pm; r < 1300*pm/float64(Z); r += 1.0 * pm / float64(Z) {
 		xy := plotter.XY{
-			X: rho,
-			Y: radialProb,
+			X: r,
+			Y: wave.RadialProbability(w, r),
 		}
 		xys = append(xys, xy)
 	}
@@ -329,7 +602,7 @@ func getValues(n, l, m int) plotter.XYs {
 	for i := range xys {
 		xys[i].X /= pm
 	}
-	return xys
+	return xys, nil
 }
 
 // Line is a plotter line.
@@ -371,297 +644,35 @@ func genPlot(dstPath string, elems ...Line) error {
 	return nil
 }
 
-// RadialProb returns the radial probability based on the given radius, r, and
-// psi for the s-orbital.
-//
-// NOTE: the returned probability is not yet normalized.
-func RadialProb(r, psi float64) float64 {
-	// area of sphere.
-	area := 4.0 * math.Pi * math.Pow(r, 2)
-	// radial probability = area * psi^2.
-	return area * math.Pow(psi, 2)
-}
-
-// Orbitals returns the psi function of the orbital with the specified principal
-// quantum number, n, azimuthal quantum number, l, and magnetic quantum number,
-// m.
-//
-//    rho (ρ):   radial distance (radius)
-//    theta (θ): inclination (angular)
-//    phi (φ):   azimuth (angular)
-func Orbitals(n, l, m int) func(rho, theta, phi float64) float64 {
-	if !(n >= 1) {
-		panic(fmt.Errorf("invalid n; expected n >= 1, got %d", n))
-	}
-	if !(0 <= l && l < n) {
-		panic(fmt.Errorf("invalid l; expected 0 <= l < n, got %d", l))
-	}
-	if !(-l <= m && m <= l) {
-		panic(fmt.Errorf("invalid m; expected -l <= m <= +l, got %d", m))
-	}
-	switch n {
-	case 1:
-		// 1s-orbital (n=1, l=0, m=0)
-		return psi1SOrbital
-	case 2:
-		switch l {
-		case 0:
-			// 2s-orbital (n=2, l=0, m=0)
-			return psi2SOrbital
-		case 1:
-			// 2p-orbitals (n=2, l=1, m={-1,0,1})
-			return psi2POrbital(m)
-		}
-	case 3:
-		switch l {
-		case 0:
-			// 3s-orbital (n=3, l=0, m=0)
-			return psi3SOrbital
-		case 1:
-			// 3p-orbitals (n=3, l=1, m={-1,0,1})
-			return psi3POrbital(m)
-		case 2:
-			// 3d-orbitals (n=3, l=2, m={-2,-1,0,1,2})
-			return psi3DOrbital(m)
-		}
-	}
-	panic(fmt.Errorf("support for (n=%d, l=%d, m=%d)-orbital not yet implemented", n, l, m))
-}
-
-// === [ s-orbitals ] ==========================================================
-
-// psi1SOrbital returns the psi function of the 1s-orbital (n=1, l=0, m=0).
-//
-// ref: https://chemistrygod.com/atomic-orbital#s-orbital
-func psi1SOrbital(rho, theta, phi float64) float64 {
-	// a_0 is the Bohr radius, and rho is the radius.
-	return (1.0 / math.SqrtPi) * math.Pow(1.0/a0, 3.0/2.0) * math.Exp(-rho/a0)
-}
-
-// psi2SOrbital returns the psi function of the 2s-orbital (n=2, l=0, m=0).
-//
-// ref: https://chemistrygod.com/atomic-orbital#s-orbital
-func psi2SOrbital(rho, theta, phi float64) float64 {
-	// a_0 is the Bohr radius, and rho is the radius.
-	return (1.0 / (math.Sqrt(32) * math.SqrtPi)) * math.Pow(1.0/a0, 3.0/2.0) * (2.0 - rho/a0) * math.Exp(-rho/(2*a0))
-}
-
-// psi3SOrbital returns the psi function of the 2s-orbital (n=3, l=0, m=0).
-//
-// ref: https://chemistrygod.com/atomic-orbital#s-orbital
-func psi3SOrbital(rho, theta, phi float64) float64 {
-	// a_0 is the Bohr radius, and rho is the radius.
-	return (1.0 / (81 * math.Sqrt(3) * math.SqrtPi)) * math.Pow(1.0/a0, 3.0/2.0) * (27.0 - (18.0*rho)/a0 + (2*math.Pow(rho, 2))/math.Pow(a0, 2)) * math.Exp(-rho/(3*a0))
-}
-
-// === [ p-orbitals ] ==========================================================
-
-// psi2POrbital returns the psi function of the 2p-orbitals (n=2, l=1,
-// m={-1,0,1})
-//
-//    2p_z: k=0
-//    2p_x: k=+1
-//    2p_y: k=-1
-//
-// ref: https://chemistrygod.com/atomic-orbital#p-orbital
-func psi2POrbital(m int) func(rho, theta, phi float64) float64 {
-	// a_0 is the Bohr radius, and rho is the radius.
-	switch m {
-	case 0:
-		// 2p-orbital (n=2, l=1, m=0)
-		return func(rho, theta, phi float64) float64 {
-			return (1.0 / (math.Sqrt(32) * math.SqrtPi)) * math.Pow(1.0/a0, 3.0/2.0) * (rho / a0) * math.Exp(-rho/(2*a0)) * math.Cos(theta)
-		}
-	case -1, +1:
-		// 2p-orbitals (n=2, l=1, m=+-1)
-		return func(rho, theta, phi float64) float64 {
-			return (1.0 / (math.Sqrt(64) * math.SqrtPi)) * math.Pow(1.0/a0, 3.0/2.0) * (rho / a0) * math.Exp(-rho/(2*a0)) * math.Sin(theta) * real(cmplx.Exp(complex(float64(m), 0)*1i*complex(phi, 0)))
-		}
-	}
-	panic("unreachable")
-}
-
-// psi3POrbital returns the psi function of the 3p-orbitals (n=3, l=1,
-// m={-1,0,1})
-//
-// ref: https://chemistrygod.com/atomic-orbital#p-orbital
-func psi3POrbital(m int) func(rho, theta, phi float64) float64 {
-	// a_0 is the Bohr radius, and rho is the radius.
-	switch m {
-	case 0:
-		// 3p-orbital (n=3, l=1, m=0)
-		return func(rho, theta, phi float64) float64 {
-			return (1.0 / 81.0) * (math.Sqrt(2) / math.SqrtPi) * math.Pow(1.0/a0, 3.0/2.0) * (6*rho/a0 - math.Pow(rho, 2)/math.Pow(a0, 2)) * math.Exp(-rho/(3*a0)) * math.Cos(theta)
-		}
-	case -1, +1:
-		// 3p-orbitals (n=3, l=1, m=+-1)
-		return func(rho, theta, phi float64) float64 {
-			// TODO: verify if `e^{-i phi}` should be `e^{+-i phi}`
-			return (1.0 / (81.0 * math.SqrtPi)) * math.Pow(1.0/a0, 3.0/2.0) * (6*rho/a0 - math.Pow(rho, 2)/math.Pow(a0, 2)) * math.Exp(-rho/(3*a0)) * math.Sin(theta) * real(cmplx.Exp(complex(float64(m), 0)*1i*complex(phi, 0)))
-		}
-	}
-	panic("unreachable")
-}
-
-// === [ d-orbitals ] ==========================================================
-
-// psi3DOrbital returns the psi function of the 3d-orbitals (n=3, l=2,
-// m={-2,-1,0,1,2})
-//
-// ref: https://chemistrygod.com/atomic-orbital#d-orbital
-func psi3DOrbital(m int) func(rho, theta, phi float64) float64 {
-	// a_0 is the Bohr radius, and rho is the radius.
-	switch m {
-	case 0:
-		// 3d-orbital (n=3, l=2, m=0)
-		return func(rho, theta, phi float64) float64 {
-			return (1.0 / (81.0 * math.Sqrt(6) * math.SqrtPi)) * math.Pow(1.0/a0, 3.0/2.0) * math.Pow(rho/a0, 2) * math.Exp(-rho/(3*a0)) * (3*math.Pow(math.Cos(theta), 2) - 1)
-		}
-	case -1, +1:
-		// 3d-orbitals (n=3, l=2, m=+-1)
-		return func(rho, theta, phi float64) float64 {
-			return (1.0 / (81.0 * math.SqrtPi)) * math.Pow(1.0/a0, 3.0/2.0) * math.Pow(rho/a0, 2) * math.Exp(-rho/(3*a0)) * math.Sin(theta) * math.Cos(theta) * real(cmplx.Exp(complex(float64(m), 0)*1i*complex(phi, 0)))
-		}
-	case -2, +2:
-		// 3d-orbitals (n=3, l=2, m=+-2)
-		return func(rho, theta, phi float64) float64 {
-			return (1.0 / (162.0 * math.SqrtPi)) * math.Pow(1.0/a0, 3.0/2.0) * math.Pow(rho/a0, 2) * math.Exp(-rho/(3*a0)) * math.Pow(math.Sin(theta), 2) * real(cmplx.Exp(complex(float64(m), 0)*1i*complex(phi, 0)))
-		}
-	}
-	panic("unreachable")
-}
-
-// === [ Hybrid wave functions ] ===============================================
-
-// --- [ sp hybrid wave function ] ---------------------------------------------
-
-// psiSPHybridOrbitals holds the psi functions of the sp hybrid orbitals.
-//
-// ref: https://winter.group.shef.ac.uk/orbitron/AO-hybrids/sp/equations.html
-var psiSPHybridOrbitals = []func(rho, theta, phi float64) float64{
-	// sp_1
-	func(rho, theta, phi float64) float64 {
-		const m_z = 0 // z: m=0
-		return (1.0 / math.Sqrt2) * (psi2SOrbital(rho, theta, phi) + psi2POrbital(m_z)(rho, theta, phi))
-	},
-	// sp_2
-	func(rho, theta, phi float64) float64 {
-		const m_z = 0 // z: m=0
-		return (1.0 / math.Sqrt2) * (psi2SOrbital(rho, theta, phi) - psi2POrbital(m_z)(rho, theta, phi))
-	},
-}
-
-// --- [ sp^2 hybrid wave function ] -------------------------------------------
-
-// psiSP2HybridOrbitals holds the psi functions of the sp^2 hybrid orbitals.
-//
-// ref: https://winter.group.shef.ac.uk/orbitron/AO-hybrids/sp2/equations.html
-var psiSP2HybridOrbitals = []func(rho, theta, phi float64) float64{
-	// sp^2_1
-	func(rho, theta, phi float64) float64 {
-		const m_z = 0 // z: m=0
-		return (1.0 / math.Sqrt(3)) * (psi2SOrbital(rho, theta, phi) + math.Sqrt2*psi2POrbital(m_z)(rho, theta, phi))
-	},
-	// sp^2_2
-	func(rho, theta, phi float64) float64 {
-		const (
-			m_x = +1 // x: m=+1
-			m_y = -1 // y: m=-1
-		)
-		return (1.0 / math.Sqrt(3)) * (psi2SOrbital(rho, theta, phi) - (1.0/math.Sqrt2)*psi2POrbital(m_x)(rho, theta, phi) + math.Sqrt(3.0/2.0)*psi2POrbital(m_y)(rho, theta, phi))
-	},
-	// sp^2_3
-	func(rho, theta, phi float64) float64 {
-		const (
-			m_x = +1 // x: m=+1
-			m_y = -1 // y: m=-1
-		)
-		return (1.0 / math.Sqrt(3)) * (psi2SOrbital(rho, theta, phi) - (1.0/math.Sqrt2)*psi2POrbital(m_x)(rho, theta, phi) - math.Sqrt(3.0/2.0)*psi2POrbital(m_y)(rho, theta, phi))
-	},
-}
-
-// --- [ sp^3 hybrid wave function ] -------------------------------------------
-
-// psiSP3HybridOrbitals holds the psi functions of the sp^3 hybrid orbitals.
-//
-// ref: https://winter.group.shef.ac.uk/orbitron/AO-hybrids/sp3/equations.html
-var psiSP3HybridOrbitals = []func(rho, theta, phi float64) float64{
-	// sp^3_1
-	func(rho, theta, phi float64) float64 {
-		const (
-			m_z = 0  // z: m=0
-			m_x = +1 // x: m=+1
-			m_y = -1 // y: m=-1
-		)
-		return (1.0 / 2.0) * (psi2SOrbital(rho, theta, phi) + psi2POrbital(m_x)(rho, theta, phi) + psi2POrbital(m_y)(rho, theta, phi) + psi2POrbital(m_z)(rho, theta, phi))
-	},
-	// sp^3_2
-	func(rho, theta, phi float64) float64 {
-		const (
-			m_z = 0  // z: m=0
-			m_x = +1 // x: m=+1
-			m_y = -1 // y: m=-1
-		)
-		return (1.0 / 2.0) * (psi2SOrbital(rho, theta, phi) + psi2POrbital(m_x)(rho, theta, phi) - psi2POrbital(m_y)(rho, theta, phi) - psi2POrbital(m_z)(rho, theta, phi))
-	},
-	// sp^3_3
-	func(rho, theta, phi float64) float64 {
-		const (
-			m_z = 0  // z: m=0
-			m_x = +1 // x: m=+1
-			m_y = -1 // y: m=-1
-		)
-		return (1.0 / 2.0) * (psi2SOrbital(rho, theta, phi) - psi2POrbital(m_x)(rho, theta, phi) + psi2POrbital(m_y)(rho, theta, phi) - psi2POrbital(m_z)(rho, theta, phi))
-	},
-	// sp^3_4
-	func(rho, theta, phi float64) float64 {
-		const (
-			m_z = 0  // z: m=0
-			m_x = +1 // x: m=+1
-			m_y = -1 // y: m=-1
-		)
-		return (1.0 / 2.0) * (psi2SOrbital(rho, theta, phi) - psi2POrbital(m_x)(rho, theta, phi) - psi2POrbital(m_y)(rho, theta, phi) + psi2POrbital(m_z)(rho, theta, phi))
-	},
-}
-
-// ### [ Helper functions ] ####################################################
-
-// writeJsonFile marshals ps into JSON format, writing to dstPath.
-func writeJsonFile(dstPath string, ps []orb.CartesianPoint) error {
-	f, err := os.Create(dstPath)
+// writeModelFile stores the points of a 3D-model in the output format of conf
+// (see createModelFile).
+func writeModelFile(conf *config, dstPath string, md *export.Metadata, ps []orb.CartesianPoint) error {
+	pw, err := createModelFile(conf, dstPath, md)
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	defer f.Close()
-	enc := json.NewEncoder(f)
 	for _, p := range ps {
-		if err := enc.Encode(p); err != nil {
+		if err := pw.WritePoint(p); err != nil {
+			pw.Close()
 			return errors.WithStack(err)
 		}
 	}
-	return nil
-}
-
-// writeObjFile stroes the points in OBJ format.
-//
-// Example file:
-//
-//    v 2.00000 0.00000 0.00000
-//    v 2.00000 1.00000 0.00000
-//    v 1.99037 0.00000 0.19603
-func writeObjFile(dstPath string, ps []orb.CartesianPoint) error {
-	f, err := os.Create(dstPath)
-	if err != nil {
+	if err := pw.Close(); err != nil {
 		return errors.WithStack(err)
 	}
-	defer f.Close()
-	bw := bufio.NewWriter(f)
-	defer bw.Flush()
-	for _, p := range ps {
-		// TODO: Also include probablility? Perhaps as colour or transparency?
-		if _, err := fmt.Fprintf(bw, "v %.1f %.1f %.1f\n", float64(p.X), float64(p.Y), float64(p.Z)); err != nil {
-			return errors.WithStack(err)
-		}
-	}
 	return nil
 }
+
+// createModelFile creates a 3D-model file in the output format of conf, to
+// which points are written one at a time. In OBJ format, vertices are coloured
+// by the sign of psi if conf.signColors is set. The metadata md precedes the
+// points.
+func createModelFile(conf *config, dstPath string, md *export.Metadata) (export.PointWriter, error) {
+	switch conf.format {
+	case "json":
+		return export.CreateJSONFile(dstPath, md)
+	case "obj":
+		return export.CreateObjFile(dstPath, md, conf.signColors)
+	}
+	return nil, errors.Errorf("support for output format %q not yet implemented", conf.format)
+}
@@ -0,0 +1,408 @@
+// Package mesh implements extraction of isosurface (boundary surface) meshes of
+// electron orbitals, using the marching cubes algorithm.
+package mesh
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sync/atomic"
+
+	"github.com/mewmew/orbitals/prune"
+	"github.com/mewmew/orbitals/sample"
+	"github.com/mewmew/orbitals/wave"
+	"github.com/pkg/errors"
+)
+
+// === [ Marching cubes ] ======================================================
+
+// Mesh is a triangle mesh.
+type Mesh struct {
+	// Vertex positions relative to the grid center; in units of grid steps as
+	// extracted by MarchingCubes, and in picometer as returned by FromPsi and
+	// Enclosing.
+	Vertices [][3]float64
+	// Unit normal of each vertex.
+	Normals [][3]float64
+	// Triangles, as counter-clockwise vertex indices (seen from the outside).
+	Faces [][3]int
+}
+
+// MarchingCubes extracts the isosurface enclosing the region where sign*value
+// > iso of the given grid, using the marching cubes algorithm. The sign is +1
+// for positive lobes and -1 for negative lobes.
+//
+// The grid is padded with values outside of the region, so that regions
+// clipped by the grid boundary are capped and the resulting mesh is watertight.
+// Vertices on shared cube edges are shared between triangles.
+//
+// ref: http://paulbourke.net/geometry/polygonise/
+func MarchingCubes(g *sample.Grid, iso, sign float64) *Mesh {
+	pad := sign * (iso - 1)
+	// f returns the signed value at the grid point (i, j, k).
+	f := func(i, j, k int) float64 {
+		return sign * g.At(i, j, k, pad)
+	}
+	// normal returns the outward normal at the grid point (i, j, k), as the
+	// negated gradient of f.
+	normal := func(i, j, k int) [3]float64 {
+		return [3]float64{
+			-(f(i+1, j, k) - f(i-1, j, k)),
+			-(f(i, j+1, k) - f(i, j-1, k)),
+			-(f(i, j, k+1) - f(i, j, k-1)),
+		}
+	}
+	m := &Mesh{}
+	// Index of vertex on each grid edge, keyed by (grid point index, axis) of
+	// the edge start.
+	edgeVertex := make(map[[2]int]int)
+	// Padded grid dimension, for computing unique keys of grid edges.
+	pn := g.N + 2
+	center := float64(g.N-1) / 2
+	for i := -1; i < g.N; i++ {
+		for j := -1; j < g.N; j++ {
+			for k := -1; k < g.N; k++ {
+				// Classify cube corners.
+				var vals [8]float64
+				config := 0
+				for c := 0; c < 8; c++ {
+					ci, cj, ck := i+c&1, j+c>>1&1, k+c>>2&1
+					vals[c] = f(ci, cj, ck)
+					if vals[c] > iso {
+						config |= 1 << uint(c)
+					}
+				}
+				if config == 0 || config == 0xFF {
+					continue
+				}
+				// vertex returns the index of the mesh vertex on the given cube
+				// edge, creating the vertex if not yet present.
+				vertex := func(edge int) int {
+					c0, c1 := mcEdgeCorners[edge][0], mcEdgeCorners[edge][1]
+					i0, j0, k0 := i+c0&1, j+c0>>1&1, k+c0>>2&1
+					i1, j1, k1 := i+c1&1, j+c1>>1&1, k+c1>>2&1
+					key := [2]int{((i0+1)*pn+(j0+1))*pn + (k0 + 1), edge / 4}
+					if idx, ok := edgeVertex[key]; ok {
+						return idx
+					}
+					t := (iso - vals[c0]) / (vals[c1] - vals[c0])
+					n0, n1 := normal(i0, j0, k0), normal(i1, j1, k1)
+					pos := [3]float64{
+						float64(i0) + t*float64(i1-i0) - center,
+						float64(j0) + t*float64(j1-j0) - center,
+						float64(k0) + t*float64(k1-k0) - center,
+					}
+					var norm [3]float64
+					for axis := range norm {
+						norm[axis] = n0[axis] + t*(n1[axis]-n0[axis])
+					}
+					idx := len(m.Vertices)
+					m.Vertices = append(m.Vertices, pos)
+					m.Normals = append(m.Normals, unitVector(norm))
+					edgeVertex[key] = idx
+					return idx
+				}
+				for _, tri := range mcTriangles[config] {
+					m.Faces = append(m.Faces, [3]int{vertex(tri[0]), vertex(tri[1]), vertex(tri[2])})
+				}
+			}
+		}
+	}
+	return m
+}
+
+// Scale scales the vertex positions of the mesh by the given factor (e.g. the
+// grid step in picometer, to convert vertex positions from units of grid
+// steps to picometer).
+func (m *Mesh) Scale(factor float64) {
+	for i := range m.Vertices {
+		for axis := range m.Vertices[i] {
+			m.Vertices[i][axis] *= factor
+		}
+	}
+}
+
+// unitVector returns the unit vector in the direction of v, or v if v is the
+// zero vector.
+func unitVector(v [3]float64) [3]float64 {
+	l := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+	if l == 0 {
+		return v
+	}
+	return [3]float64{v[0] / l, v[1] / l, v[2] / l}
+}
+
+// --- [ Marching cubes tables ] -----------------------------------------------
+
+// Cube corners are indexed by c = x | y<<1 | z<<2, for the corner offsets (x, y,
+// z) in {0,1}^3. Cube edges are indexed by axis*4 + the two remaining corner
+// offset bits, where axis is the axis along which the edge runs.
+
+// mcEdgeCorners maps from cube edge to the corners of the edge.
+var mcEdgeCorners [12][2]int
+
+// mcTriangles maps from cube configuration (bit c set if corner c is inside the
+// region) to triangles, as cube edges of the triangle vertices.
+var mcTriangles [256][][3]int
+
+func init() {
+	// Cube edges.
+	for axis := 0; axis < 3; axis++ {
+		for rest := 0; rest < 4; rest++ {
+			// Insert bit 0 at position axis of rest.
+			lo := rest & (1<<uint(axis) - 1)
+			hi := (rest >> uint(axis)) << uint(axis+1)
+			c0 := hi | lo
+			mcEdgeCorners[axis*4+rest] = [2]int{c0, c0 | 1<<uint(axis)}
+		}
+	}
+	for config := range mcTriangles {
+		mcTriangles[config] = mcTriangulate(config)
+	}
+}
+
+// mcTriangulate returns the triangles of the given cube configuration.
+//
+// The triangles are derived by walking the boundary of each cube face counter-
+// clockwise (seen from the outside of the cube), and cutting off each arc of
+// inside corners by a segment from the edge where the arc is left to the edge
+// where the arc is entered. Since neighbouring cubes cut their shared face
+// identically, the resulting surface is watertight. The segments of all faces
+// form closed loops, which are triangulated as fans without diagonals on cube
+// faces; thus every mesh edge is shared by exactly two triangles.
+func mcTriangulate(config int) [][3]int {
+	inside := func(c int) bool {
+		return config&(1<<uint(c)) != 0
+	}
+	// next maps from the edge where a segment starts to the edge where it ends.
+	next := make(map[int]int)
+	for axis := 0; axis < 3; axis++ {
+		for side := 0; side < 2; side++ {
+			corners := mcFaceCorners(axis, side)
+			// Locate an outside corner to start the walk from.
+			start := -1
+			for i, c := range corners {
+				if !inside(c) {
+					start = i
+					break
+				}
+			}
+			if start == -1 {
+				continue
+			}
+			enter := -1
+			for n := 1; n <= 4; n++ {
+				c0 := corners[(start+n-1)%4]
+				c1 := corners[(start+n)%4]
+				switch {
+				case !inside(c0) && inside(c1):
+					enter = mcEdge(c0, c1)
+				case inside(c0) && !inside(c1):
+					next[mcEdge(c0, c1)] = enter
+				}
+			}
+		}
+	}
+	// Collect loops and triangulate them as fans.
+	var tris [][3]int
+	for len(next) > 0 {
+		var loop []int
+		first := -1
+		for edge := range next {
+			if first == -1 || edge < first {
+				first = edge
+			}
+		}
+		for edge := first; ; {
+			loop = append(loop, edge)
+			to := next[edge]
+			delete(next, edge)
+			if to == first {
+				break
+			}
+			edge = to
+		}
+		// Rotate the loop to start at the apex of the fan; the first edge whose
+		// diagonals don't lie on a cube face. Loops passing through both segments
+		// of a face (e.g. where the inside corners of a face are diagonally
+		// opposite) would otherwise get a diagonal on that face, which may also be
+		// a diagonal of the neighbouring cube; i.e. a mesh edge shared by four
+		// triangles.
+		apex := 0
+		for a := range loop {
+			if !mcFanOnFace(loop, a) {
+				apex = a
+				break
+			}
+		}
+		loop = append(loop[apex:], loop[:apex]...)
+		// The loops wind clockwise seen from the outside of the region; reverse
+		// the winding of the triangles to make them counter-clockwise.
+		for i := 1; i+1 < len(loop); i++ {
+			tris = append(tris, [3]int{loop[0], loop[i+1], loop[i]})
+		}
+	}
+	return tris
+}
+
+// mcFanOnFace reports whether any diagonal of the fan triangulation of the
+// given loop of cube edges, with apex loop[apex], lies on a cube face.
+func mcFanOnFace(loop []int, apex int) bool {
+	n := len(loop)
+	for i := 2; i+1 < n; i++ {
+		if mcCoplanar(loop[apex], loop[(apex+i)%n]) {
+			return true
+		}
+	}
+	return false
+}
+
+// mcCoplanar reports whether the given cube edges lie on a common cube face.
+func mcCoplanar(e0, e1 int) bool {
+	corners := [4]int{mcEdgeCorners[e0][0], mcEdgeCorners[e0][1], mcEdgeCorners[e1][0], mcEdgeCorners[e1][1]}
+	for axis := uint(0); axis < 3; axis++ {
+		same := true
+		for _, c := range corners[1:] {
+			if c>>axis&1 != corners[0]>>axis&1 {
+				same = false
+				break
+			}
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
+
+// mcFaceCorners returns the corners of the cube face perpendicular to the given
+// axis at the given side (0 or 1), in counter-clockwise order seen from the
+// outside of the cube.
+func mcFaceCorners(axis, side int) [4]int {
+	// The two remaining axes, such that (axis, u, v) is right-handed.
+	u, v := (axis+1)%3, (axis+2)%3
+	base := side << uint(axis)
+	corners := [4]int{
+		base,
+		base | 1<<uint(u),
+		base | 1<<uint(u) | 1<<uint(v),
+		base | 1<<uint(v),
+	}
+	if side == 0 {
+		// Seen from the outside, the face normal points along the negative axis,
+		// which reverses the winding.
+		corners[1], corners[3] = corners[3], corners[1]
+	}
+	return corners
+}
+
+// mcEdge returns the cube edge between the given adjacent corners.
+func mcEdge(c0, c1 int) int {
+	for edge, corners := range mcEdgeCorners {
+		if (corners[0] == c0 && corners[1] == c1) || (corners[0] == c1 && corners[1] == c0) {
+			return edge
+		}
+	}
+	panic(fmt.Errorf("corners %d and %d are not adjacent", c0, c1))
+}
+
+// FromPsi returns the isosurface meshes of the positive and
+// negative lobes of the electron orbital with the specified complex-valued wave
+// function, psi, at the given iso-value of the probability density |psi|^2. The
+// lobes are the regions where |psi|^2 > iso, split by the sign of psi for
+// real-valued wave functions; the isosurface of complex-valued wave functions is
+// returned as the positive mesh, with an empty negative mesh (see lobeGrid).
+// The grid covers the given extent (see sample.Extent.Cartesian), and the
+// vertex positions are in picometer.
+//
+// An error is returned if the extent is invalid.
+func FromPsi(ext sample.Extent, Psi wave.ComplexPsiFunc, iso float64) (positive, negative *Mesh, err error) {
+	g, err := lobeGrid(ext, Psi)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return lobeMeshes(ext, g, iso)
+}
+
+// Enclosing returns the isosurface meshes of the positive
+// and negative lobes of the electron orbital with the specified complex-valued
+// wave function, psi, such that the isosurfaces enclose the given fraction
+// (e.g. 0.9 for 90%) of the probability. The iso-value of the probability
+// density |psi|^2 is computed from the sampled distribution, and returned as
+// iso. The lobes of real-valued wave functions are split by the sign of psi
+// (see FromPsi). The grid covers the given extent (see
+// sample.Extent.Cartesian), and the vertex positions are in picometer.
+//
+// Since the iso-value is derived from the probability distribution rather than
+// fixed, the boundary surfaces of orbitals of different n and Z are comparable,
+// and independent of the grid resolution.
+//
+// An error is returned if the extent or the fraction is invalid (see
+// prune.Enclosing).
+func Enclosing(ext sample.Extent, Psi wave.ComplexPsiFunc, frac float64) (positive, negative *Mesh, iso float64, err error) {
+	g, err := lobeGrid(ext, Psi)
+	if err != nil {
+		return nil, nil, 0, errors.WithStack(err)
+	}
+	iso, err = prune.Enclosing(g.Vals, frac)
+	if err != nil {
+		return nil, nil, 0, errors.WithStack(err)
+	}
+	positive, negative, err = lobeMeshes(ext, g, iso)
+	if err != nil {
+		return nil, nil, 0, errors.WithStack(err)
+	}
+	return positive, negative, iso, nil
+}
+
+// lobeMeshes returns the isosurface meshes of the positive and negative lobes
+// of the given grid within the given extent, at the given iso-value, with
+// vertex positions in picometer.
+func lobeMeshes(ext sample.Extent, g *sample.Grid, iso float64) (positive, negative *Mesh, err error) {
+	step, _, _, err := ext.Cartesian()
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	positive = MarchingCubes(g, iso, +1)
+	negative = MarchingCubes(g, iso, -1)
+	positive.Scale(step / wave.Picometer)
+	negative.Scale(step / wave.Picometer)
+	return positive, negative, nil
+}
+
+// lobeGrid returns the grid of the probability density |psi|^2 within the given
+// extent, signed by the sign of psi for real-valued wave functions; i.e. the
+// absolute values of the grid are the normalized probability density (see
+// sample.DensityMode), and for real-valued wave functions, the grid equals that
+// of the signed probability density Re(psi) |psi| (see sample.SignedMode).
+//
+// Complex-valued wave functions (e.g. complex orbitals with m != 0) have no
+// sign, as their phase winds around the z-axis; splitting them by the sign of
+// Re(psi) would cut their isosurface into wedges. Thus, if psi has a
+// non-negligible imaginary part at any grid point, the grid is unsigned.
+func lobeGrid(ext sample.Extent, Psi wave.ComplexPsiFunc) (*sample.Grid, error) {
+	// isComplex is set if psi has a non-negligible imaginary part at any grid
+	// point; the grid is evaluated concurrently.
+	var isComplex int32
+	signed := func(rho, theta, phi float64) complex128 {
+		psi := Psi(rho, theta, phi)
+		amp := cmplx.Abs(psi)
+		if math.Abs(imag(psi)) > 1e-9*amp {
+			atomic.StoreInt32(&isComplex, 1)
+		}
+		if real(psi) < 0 {
+			amp = -amp
+		}
+		return complex(amp, 0)
+	}
+	g, err := sample.CartesianGrid(sample.SignedMode, ext, signed)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if atomic.LoadInt32(&isComplex) != 0 {
+		for i, v := range g.Vals {
+			g.Vals[i] = math.Abs(v)
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,180 @@
+package mesh
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/mewmew/orbitals/prune"
+	"github.com/mewmew/orbitals/sample"
+	"github.com/mewmew/orbitals/wave"
+)
+
+func TestMarchingCubesWatertight(t *testing.T) {
+	golden := []struct {
+		name string
+		g    *sample.Grid
+		iso  float64
+	}{}
+	// Each configuration of a single cube.
+	for config := 0; config < 256; config++ {
+		g := &sample.Grid{N: 2, Vals: make([]float64, 8)}
+		for c := 0; c < 8; c++ {
+			// Grid point (i, j, k) of cube corner c = i | j<<1 | k<<2.
+			i, j, k := c&1, c>>1&1, c>>2&1
+			g.Vals[(i*2+j)*2+k] = -1
+			if config&(1<<uint(c)) != 0 {
+				g.Vals[(i*2+j)*2+k] = 1
+			}
+		}
+		golden = append(golden, struct {
+			name string
+			g    *sample.Grid
+			iso  float64
+		}{name: fmt.Sprintf("config %08b", config), g: g, iso: 0})
+	}
+	// Random grids, with ambiguous faces and regions clipped by the grid
+	// boundary.
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		const n = 9
+		g := &sample.Grid{N: n, Vals: make([]float64, n*n*n)}
+		for j := range g.Vals {
+			g.Vals[j] = 2*r.Float64() - 1
+		}
+		golden = append(golden, struct {
+			name string
+			g    *sample.Grid
+			iso  float64
+		}{name: fmt.Sprintf("random %d", i), g: g, iso: 0.1 * float64(i%3)})
+	}
+	// Lobes of orbitals.
+	var ws []wave.Wavefunction
+	for _, spec := range [][4]int{{3, 2, 0, int(wave.RealBasis)}, {4, 3, -3, int(wave.ComplexBasis)}, {4, 2, 1, int(wave.RealBasis)}} {
+		w, err := wave.NewHydrogenic(wave.Basis(spec[3]), 1, spec[0], spec[1], spec[2])
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ws = append(ws, w)
+	}
+	hs, err := wave.Hybrids("sp3", 1)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ws = append(ws, hs[0])
+	for _, w := range ws {
+		ext, err := sample.ExtentOf(w)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext.Grid = 31
+		g, err := lobeGrid(ext, w.Psi)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		for _, frac := range []float64{0.5, 0.9, 0.999} {
+			iso, err := prune.Enclosing(g.Vals, frac)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			golden = append(golden, struct {
+				name string
+				g    *sample.Grid
+				iso  float64
+			}{name: fmt.Sprintf("%s (%g)", w.Label(), frac), g: g, iso: iso})
+		}
+	}
+	for _, g := range golden {
+		for _, sign := range []float64{+1, -1} {
+			m := MarchingCubes(g.g, g.iso, sign)
+			if err := checkWatertight(m); err != nil {
+				t.Errorf("%s (sign %+g): %v", g.name, sign, err)
+			}
+		}
+	}
+}
+
+// checkWatertight reports an error if the given mesh is not a closed, consistently
+// oriented surface; i.e. unless every edge is shared by exactly two faces,
+// which traverse the edge in opposite directions.
+func checkWatertight(m *Mesh) error {
+	// Number of faces traversing each directed edge.
+	edges := make(map[[2]int]int)
+	for _, f := range m.Faces {
+		if f[0] == f[1] || f[1] == f[2] || f[2] == f[0] {
+			return fmt.Errorf("degenerate face %v", f)
+		}
+		for i := 0; i < 3; i++ {
+			edges[[2]int{f[i], f[(i+1)%3]}]++
+		}
+	}
+	for e, n := range edges {
+		if n != 1 {
+			return fmt.Errorf("edge %v traversed by %d faces in the same direction", e, n)
+		}
+		if edges[[2]int{e[1], e[0]}] != 1 {
+			return fmt.Errorf("edge %v not shared by two faces", e)
+		}
+	}
+	return nil
+}
+
+func TestEnclosingComplex(t *testing.T) {
+	golden := []struct {
+		basis   wave.Basis
+		n, l, m int
+		// Negative lobes expected; complex-valued orbitals have a single
+		// isosurface of |psi|^2.
+		negative bool
+	}{
+		{basis: wave.RealBasis, n: 2, l: 1, m: 1, negative: true},
+		{basis: wave.RealBasis, n: 3, l: 2, m: -2, negative: true},
+		{basis: wave.ComplexBasis, n: 3, l: 2, m: 0, negative: true},
+		{basis: wave.ComplexBasis, n: 2, l: 1, m: 1, negative: false},
+		{basis: wave.ComplexBasis, n: 3, l: 2, m: -1, negative: false},
+		{basis: wave.ComplexBasis, n: 4, l: 3, m: -3, negative: false},
+	}
+	for _, g := range golden {
+		w, err := wave.NewHydrogenic(g.basis, 1, g.n, g.l, g.m)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext, err := sample.ExtentOf(w)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext.Grid = 31
+		positive, negative, _, err := Enclosing(ext, w.Psi, 0.9)
+		if err != nil {
+			t.Errorf("%s: unable to generate meshes; %v", w.Label(), err)
+			continue
+		}
+		if got := len(negative.Faces) > 0; got != g.negative {
+			t.Errorf("%s: negative lobes mismatch; expected %v, got %v", w.Label(), g.negative, got)
+		}
+		if g.negative {
+			continue
+		}
+		if err := checkWatertight(positive); err != nil {
+			t.Errorf("%s: %v", w.Label(), err)
+		}
+		// |psi|^2 of complex orbitals is symmetric about the z-axis; thus the
+		// isosurface is not cut into wedges by the sign of Re(psi).
+		var quadrants [4]bool
+		for _, v := range positive.Vertices {
+			switch {
+			case v[0] > 0 && v[1] > 0:
+				quadrants[0] = true
+			case v[0] < 0 && v[1] > 0:
+				quadrants[1] = true
+			case v[0] < 0 && v[1] < 0:
+				quadrants[2] = true
+			case v[0] > 0 && v[1] < 0:
+				quadrants[3] = true
+			}
+		}
+		if quadrants != [4]bool{true, true, true, true} {
+			t.Errorf("%s: isosurface cut into wedges; vertices in xy-quadrants %v", w.Label(), quadrants)
+		}
+	}
+}
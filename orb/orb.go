@@ -1,5 +1,7 @@
 package orb
 
+import "math"
+
 // SphericalCoord is a spherical (rho, theta, phi)-coordinate.
 type SphericalCoord struct {
 	// Radial distance (radius)
@@ -15,7 +17,7 @@ type SphericalPoint struct {
 	// Spherical coordinate of electron.
 	//SphericalCoord
 
-	// Radial distance (radius)
+	// Radial distance (radius) in atomic units of length (see wave.BohrRadius)
 	Rho float64
 	// Inclination (angular)
 	Theta float64
@@ -24,12 +26,38 @@ type SphericalPoint struct {
 
 	// Probability of electron occurence at the spherical coordinate.
 	Prob float64
+	// Amplitude |psi| of the wave function at the spherical coordinate, in
+	// atomic units (a_0^{-3/2}).
+	Amp float64
+	// Phase arg(psi) in radians of the wave function at the spherical
+	// coordinate; 0 or π for real-valued wave functions.
+	Phase float64
 }
 
 // CartesianPoint is a Cartesian coordinate with a probability.
 type CartesianPoint struct {
 	// X-, Y-, Z-coordinate in picometer.
-	X, Y, Z int
+	X, Y, Z float64
 	// Probability of electron occurence at the Cartesian coordinate.
 	Prob float64
+	// Amplitude |psi| of the wave function at the Cartesian coordinate, in
+	// atomic units (a_0^{-3/2}).
+	Amp float64
+	// Phase arg(psi) in radians of the wave function at the Cartesian
+	// coordinate; 0 or π for real-valued wave functions.
+	Phase float64
+}
+
+// Signed returns the signed psi value of the spherical point, i.e. the real part
+// of psi; positive and negative for the respective lobes of real-valued wave
+// functions.
+func (p SphericalPoint) Signed() float64 {
+	return p.Amp * math.Cos(p.Phase)
+}
+
+// Signed returns the signed psi value of the Cartesian point, i.e. the real part
+// of psi; positive and negative for the respective lobes of real-valued wave
+// functions.
+func (p CartesianPoint) Signed() float64 {
+	return p.Amp * math.Cos(p.Phase)
 }
@@ -0,0 +1,191 @@
+// Package prune implements strategies for pruning the points of 3D-models based
+// on their probabilities.
+package prune
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mewmew/orbitals/orb"
+	"github.com/mewmew/orbitals/wave"
+	"github.com/pkg/errors"
+)
+
+// Strategy is a strategy for pruning the points of a 3D-model, based on their
+// probabilities.
+type Strategy interface {
+	// Cutoff returns the cutoff probability for the given probabilities; points
+	// with absolute probability below the cutoff are pruned. An error is
+	// returned if the parameters of the strategy are invalid.
+	Cutoff(probs []float64) (float64, error)
+}
+
+// Abs is a pruning strategy which prunes points below the given absolute
+// probability.
+type Abs float64
+
+// Cutoff returns the cutoff probability for the given probabilities.
+func (s Abs) Cutoff(probs []float64) (float64, error) {
+	return float64(s), nil
+}
+
+// String returns the string representation of the pruning strategy.
+func (s Abs) String() string {
+	return fmt.Sprintf("probability >= %g", float64(s))
+}
+
+// Mass is a pruning strategy which keeps the most probable points
+// carrying the given fraction (e.g. 0.9 for 90%) of the total probability mass.
+// The fraction must be in (0, 1].
+type Mass float64
+
+// Cutoff returns the cutoff probability for the given probabilities.
+func (s Mass) Cutoff(probs []float64) (float64, error) {
+	return Enclosing(probs, float64(s))
+}
+
+// String returns the string representation of the pruning strategy.
+func (s Mass) String() string {
+	return fmt.Sprintf("top %g%% of probability mass", 100*float64(s))
+}
+
+// TopK is a pruning strategy which keeps the given number of most
+// probable points. Points tied with the K:th most probable point are also kept.
+type TopK int
+
+// Cutoff returns the cutoff probability for the given probabilities.
+func (s TopK) Cutoff(probs []float64) (float64, error) {
+	k := int(s)
+	if k <= 0 {
+		return math.Inf(1), nil
+	}
+	if k > len(probs) {
+		return 0, nil
+	}
+	abs := make([]float64, len(probs))
+	for i, prob := range probs {
+		abs[i] = math.Abs(prob)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(abs)))
+	return abs[k-1], nil
+}
+
+// String returns the string representation of the pruning strategy.
+func (s TopK) String() string {
+	return fmt.Sprintf("top %d points", int(s))
+}
+
+// Rel is a pruning strategy which prunes points below the given fraction
+// of the maximum probability.
+type Rel float64
+
+// Cutoff returns the cutoff probability for the given probabilities.
+func (s Rel) Cutoff(probs []float64) (float64, error) {
+	max := 0.0
+	for _, prob := range probs {
+		max = math.Max(max, math.Abs(prob))
+	}
+	return float64(s) * max, nil
+}
+
+// String returns the string representation of the pruning strategy.
+func (s Rel) String() string {
+	return fmt.Sprintf("probability >= %g * max", float64(s))
+}
+
+// Spheric prunes points based on the given pruning strategy and converts the
+// points from spherical coordinates to Cartesian coordinates in picometer.
+func Spheric(pts []orb.SphericalPoint, strategy Strategy) ([]orb.CartesianPoint, error) {
+	probs := make([]float64, len(pts))
+	for i, pt := range pts {
+		probs[i] = pt.Prob
+	}
+	threshold, err := strategy.Cutoff(probs)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var ps []orb.CartesianPoint
+	for _, pt := range pts {
+		if math.Abs(pt.Prob) < threshold {
+			continue
+		}
+		ps = append(ps, ToCartesian(pt))
+	}
+	return ps, nil
+}
+
+// ToCartesian converts the given point from spherical coordinates to Cartesian
+// coordinates in picometer.
+func ToCartesian(pt orb.SphericalPoint) orb.CartesianPoint {
+	x, y, z := wave.CartesianFromSpherical(pt.Rho, pt.Theta, pt.Phi)
+	return orb.CartesianPoint{
+		X:     x / wave.Picometer,
+		Y:     y / wave.Picometer,
+		Z:     z / wave.Picometer,
+		Prob:  pt.Prob,
+		Amp:   pt.Amp,
+		Phase: pt.Phase,
+	}
+}
+
+// Cartesian prunes points based on the given pruning strategy.
+func Cartesian(pts []orb.CartesianPoint, strategy Strategy) ([]orb.CartesianPoint, error) {
+	probs := make([]float64, len(pts))
+	for i, pt := range pts {
+		probs[i] = pt.Prob
+	}
+	threshold, err := strategy.Cutoff(probs)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var ps []orb.CartesianPoint
+	for _, pt := range pts {
+		if math.Abs(pt.Prob) < threshold {
+			continue
+		}
+		ps = append(ps, pt)
+	}
+	return ps, nil
+}
+
+// Enclosing returns the cutoff probability (or iso-value) such that the sampled
+// probabilities with absolute value at or above the cutoff account for the
+// given fraction (e.g. 0.9 for 90%) of the total absolute probability.
+//
+// An error is returned if the fraction is not in (0, 1].
+func Enclosing(probs []float64, frac float64) (float64, error) {
+	if err := checkFrac(frac); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	abs := make([]float64, 0, len(probs))
+	total := 0.0
+	for _, prob := range probs {
+		if prob == 0 {
+			continue
+		}
+		abs = append(abs, math.Abs(prob))
+		total += math.Abs(prob)
+	}
+	if len(abs) == 0 {
+		return 0, nil
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(abs)))
+	mass := 0.0
+	for _, prob := range abs {
+		mass += prob
+		if mass >= frac*total {
+			return prob, nil
+		}
+	}
+	// Rounding errors may prevent the mass from reaching the total.
+	return abs[len(abs)-1], nil
+}
+
+// checkFrac returns an error if the given enclosed fraction is not in (0, 1].
+func checkFrac(frac float64) error {
+	if !(0 < frac && frac <= 1) {
+		return errors.Errorf("invalid enclosed fraction; expected 0 < frac <= 1, got %g", frac)
+	}
+	return nil
+}
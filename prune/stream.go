@@ -0,0 +1,210 @@
+package prune
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// Accumulator accumulates the unnormalized probabilities of a 3D-model one at a
+// time, to compute the cutoff probability of a pruning strategy without storing
+// the probabilities of all points.
+type Accumulator interface {
+	// Add adds the given unnormalized probability.
+	Add(prob float64)
+	// Cutoff returns the cutoff probability of the normalized probabilities,
+	// where the sum of absolute unnormalized probabilities is total. An error is
+	// returned if the parameters of the pruning strategy are invalid.
+	Cutoff(total float64) (float64, error)
+}
+
+// NewAccumulator returns a new accumulator of the given pruning strategy.
+//
+// The memory usage of accumulators is bounded for the built-in strategies;
+// constant for Abs and Rel, proportional to K for TopK, and a fixed-size
+// histogram for Mass, which approximates the cutoff within 1/64 of its
+// value (rounded down). Accumulators of other strategies store all
+// probabilities.
+func NewAccumulator(strategy Strategy) Accumulator {
+	switch s := strategy.(type) {
+	case Abs:
+		return absAccumulator(s)
+	case Rel:
+		return &relAccumulator{frac: float64(s)}
+	case TopK:
+		return &topKAccumulator{k: int(s)}
+	case Mass:
+		return &massAccumulator{frac: float64(s), bins: make([]float64, histBins)}
+	}
+	return &sliceAccumulator{strategy: strategy}
+}
+
+// absAccumulator is the accumulator of the Abs pruning strategy.
+type absAccumulator Abs
+
+// Add adds the given unnormalized probability.
+func (acc absAccumulator) Add(prob float64) {}
+
+// Cutoff returns the cutoff probability of the normalized probabilities.
+func (acc absAccumulator) Cutoff(total float64) (float64, error) {
+	return float64(acc), nil
+}
+
+// relAccumulator is the accumulator of the Rel pruning strategy.
+type relAccumulator struct {
+	// Fraction of maximum probability.
+	frac float64
+	// Maximum absolute unnormalized probability.
+	max float64
+}
+
+// Add adds the given unnormalized probability.
+func (acc *relAccumulator) Add(prob float64) {
+	acc.max = math.Max(acc.max, math.Abs(prob))
+}
+
+// Cutoff returns the cutoff probability of the normalized probabilities.
+func (acc *relAccumulator) Cutoff(total float64) (float64, error) {
+	if total == 0 {
+		return 0, nil
+	}
+	return acc.frac * acc.max / total, nil
+}
+
+// topKAccumulator is the accumulator of the TopK pruning strategy.
+type topKAccumulator struct {
+	// Number of points kept.
+	k int
+	// Min-heap of the K largest absolute unnormalized probabilities.
+	top minHeap
+}
+
+// Add adds the given unnormalized probability.
+func (acc *topKAccumulator) Add(prob float64) {
+	abs := math.Abs(prob)
+	switch {
+	case acc.k <= 0:
+		// nothing to do.
+	case len(acc.top) < acc.k:
+		heap.Push(&acc.top, abs)
+	case abs > acc.top[0]:
+		acc.top[0] = abs
+		heap.Fix(&acc.top, 0)
+	}
+}
+
+// Cutoff returns the cutoff probability of the normalized probabilities.
+func (acc *topKAccumulator) Cutoff(total float64) (float64, error) {
+	switch {
+	case acc.k <= 0:
+		return math.Inf(1), nil
+	case len(acc.top) < acc.k, total == 0:
+		return 0, nil
+	}
+	return acc.top[0] / total, nil
+}
+
+// minHeap is a min-heap of float64 values.
+type minHeap []float64
+
+func (h minHeap) Len() int            { return len(h) }
+func (h minHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h minHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x interface{}) { *h = append(*h, x.(float64)) }
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Resolution of the logarithmic histogram of the Mass accumulator; the number
+// of bins per binary order of magnitude, and the total number of bins covering
+// all float64 exponents.
+const (
+	histSubBins = 64
+	histMinExp  = -1100
+	histBins    = (1100 + 1100) * histSubBins
+)
+
+// massAccumulator is the accumulator of the Mass pruning strategy.
+type massAccumulator struct {
+	// Fraction of probability mass.
+	frac float64
+	// Logarithmic histogram of the absolute unnormalized probability mass.
+	bins []float64
+}
+
+// Add adds the given unnormalized probability.
+func (acc *massAccumulator) Add(prob float64) {
+	if prob == 0 {
+		return
+	}
+	abs := math.Abs(prob)
+	acc.bins[histBin(abs)] += abs
+}
+
+// Cutoff returns the cutoff probability of the normalized probabilities.
+func (acc *massAccumulator) Cutoff(total float64) (float64, error) {
+	if err := checkFrac(acc.frac); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	mass := 0.0
+	for i := len(acc.bins) - 1; i >= 0; i-- {
+		if acc.bins[i] == 0 {
+			continue
+		}
+		mass += acc.bins[i]
+		if mass >= acc.frac*total {
+			return histLowerBound(i) / total, nil
+		}
+	}
+	// Rounding errors may prevent the mass from reaching the total.
+	return 0, nil
+}
+
+// histBin returns the histogram bin of the given positive value.
+func histBin(v float64) int {
+	frac, exp := math.Frexp(v) // v = frac * 2^exp, with frac in [0.5, 1).
+	return (exp-histMinExp)*histSubBins + int((frac-0.5)*2*histSubBins)
+}
+
+// histLowerBound returns the lower bound of the values of the given histogram
+// bin.
+func histLowerBound(bin int) float64 {
+	exp := bin/histSubBins + histMinExp
+	frac := 0.5 + float64(bin%histSubBins)/(2*histSubBins)
+	return math.Ldexp(frac, exp)
+}
+
+// sliceAccumulator is the accumulator of arbitrary pruning strategies, storing
+// all probabilities.
+type sliceAccumulator struct {
+	// Pruning strategy.
+	strategy Strategy
+	// Unnormalized probabilities.
+	probs []float64
+}
+
+// Add adds the given unnormalized probability.
+func (acc *sliceAccumulator) Add(prob float64) {
+	acc.probs = append(acc.probs, prob)
+}
+
+// Cutoff returns the cutoff probability of the normalized probabilities.
+func (acc *sliceAccumulator) Cutoff(total float64) (float64, error) {
+	if total != 0 {
+		for i := range acc.probs {
+			acc.probs[i] /= total
+		}
+	}
+	cutoff, err := acc.strategy.Cutoff(acc.probs)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return cutoff, nil
+}
@@ -0,0 +1,147 @@
+package prune
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// sliceStrategy is a pruning strategy without a dedicated accumulator (see
+// NewAccumulator).
+type sliceStrategy struct {
+	Strategy
+}
+
+func TestAccumulator(t *testing.T) {
+	strategies := []Strategy{
+		Abs(0),
+		Abs(1e-3),
+		Rel(0),
+		Rel(0.01),
+		Rel(1),
+		TopK(0),
+		TopK(1),
+		TopK(100),
+		TopK(1 << 20),
+		sliceStrategy{Mass(0.9)},
+		sliceStrategy{TopK(10)},
+	}
+	for _, data := range accumulatorData() {
+		for _, strategy := range strategies {
+			name := fmt.Sprintf("%s (%v)", data.name, strategy)
+			got, want, err := accumulatorCutoff(strategy, data.probs)
+			if err != nil {
+				t.Errorf("%s: unable to compute cutoff; %v", name, err)
+				continue
+			}
+			if !(got == want || math.Abs(got-want) <= 1e-12*math.Abs(want)) {
+				t.Errorf("%s: cutoff mismatch; expected %g, got %g", name, want, got)
+			}
+		}
+	}
+}
+
+func TestAccumulatorMass(t *testing.T) {
+	for _, data := range accumulatorData() {
+		for _, frac := range []float64{0.1, 0.5, 0.9, 0.99, 0.999} {
+			strategy := Mass(frac)
+			name := fmt.Sprintf("%s (%v)", data.name, strategy)
+			got, want, err := accumulatorCutoff(strategy, data.probs)
+			if err != nil {
+				t.Errorf("%s: unable to compute cutoff; %v", name, err)
+				continue
+			}
+			// The cutoff is approximated within 1/64 of its value, rounded down.
+			if !(want/(1+1.0/64) <= got && got <= want) {
+				t.Errorf("%s: cutoff mismatch; expected %g (rounded down by at most 1/64), got %g", name, want, got)
+			}
+		}
+	}
+}
+
+func TestMassInvalid(t *testing.T) {
+	probs := []float64{0.5, 0.3, 0.2}
+	for _, frac := range []float64{0, -0.5, 1.5, math.NaN()} {
+		strategy := Mass(frac)
+		if _, err := strategy.Cutoff(probs); err == nil {
+			t.Errorf("%v: expected error for invalid fraction, got nil", strategy)
+		}
+		acc := NewAccumulator(strategy)
+		for _, prob := range probs {
+			acc.Add(prob)
+		}
+		if _, err := acc.Cutoff(1); err == nil {
+			t.Errorf("%v: expected error of accumulator for invalid fraction, got nil", strategy)
+		}
+		if _, err := Cartesian(nil, strategy); err == nil {
+			t.Errorf("%v: expected error of pruning for invalid fraction, got nil", strategy)
+		}
+	}
+}
+
+// accumulatorCutoff returns the cutoff of the given unnormalized probabilities
+// computed by the accumulator of the given pruning strategy, and computed by the
+// strategy on the normalized probabilities.
+func accumulatorCutoff(strategy Strategy, probs []float64) (got, want float64, err error) {
+	acc := NewAccumulator(strategy)
+	total := 0.0
+	for _, prob := range probs {
+		acc.Add(prob)
+		total += math.Abs(prob)
+	}
+	got, err = acc.Cutoff(total)
+	if err != nil {
+		return 0, 0, err
+	}
+	normalized := make([]float64, len(probs))
+	for i, prob := range probs {
+		normalized[i] = prob
+		if total != 0 {
+			normalized[i] /= total
+		}
+	}
+	want, err = strategy.Cutoff(normalized)
+	if err != nil {
+		return 0, 0, err
+	}
+	return got, want, nil
+}
+
+// accumulatorData returns sets of unnormalized probabilities.
+func accumulatorData() []struct {
+	name  string
+	probs []float64
+} {
+	r := rand.New(rand.NewSource(1))
+	uniform := make([]float64, 10000)
+	for i := range uniform {
+		uniform[i] = r.Float64()
+	}
+	// Signed probabilities, spanning many orders of magnitude, with zeros.
+	signed := make([]float64, 10000)
+	for i := range signed {
+		switch {
+		case i%10 == 0:
+			signed[i] = 0
+		case i%2 == 0:
+			signed[i] = -math.Exp(-40 * r.Float64())
+		default:
+			signed[i] = math.Exp(-40 * r.Float64())
+		}
+	}
+	// Ties.
+	ties := make([]float64, 1000)
+	for i := range ties {
+		ties[i] = float64(i%7) * 1e-3
+	}
+	return []struct {
+		name  string
+		probs []float64
+	}{
+		{name: "uniform", probs: uniform},
+		{name: "signed", probs: signed},
+		{name: "ties", probs: ties},
+		{name: "single", probs: []float64{2.5}},
+	}
+}
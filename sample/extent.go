@@ -0,0 +1,118 @@
+package sample
+
+import (
+	"math"
+
+	"github.com/mewmew/orbitals/wave"
+	"github.com/pkg/errors"
+)
+
+// Extent specifies the sampled region and resolution of the samplers, and the
+// concurrency of grid evaluation.
+type Extent struct {
+	// Radius of the sampled region; the maximum radius of the spherical sampler,
+	// and the half side length of the cube sampled by the Cartesian, mesh and
+	// Monte Carlo samplers. Must be positive.
+	Max float64
+	// Number of grid points per axis (Cartesian and mesh samplers) or radial
+	// samples (spherical sampler); 0 to choose the resolution from Points.
+	Grid int
+	// Target number of points of the full grid of the grid samplers, from which
+	// the resolution is chosen if Grid is 0; 0 for default (DefaultCartesianGrid
+	// points per axis and DefaultSphericGrid radial samples, respectively).
+	Points int
+	// Sampling scheme of directions of the spherical sampler.
+	Sphere Sphere
+	// Approximate number of directions of the spherical sampler; 0 for
+	// default (DefaultDirections).
+	Directions int
+	// Number of concurrent workers evaluating the grids of the Cartesian,
+	// spherical and mesh samplers; 0 for the number of CPUs. The wave functions
+	// sampled by the grid samplers must be safe for concurrent use.
+	Workers int
+}
+
+// DefaultEnclosed is the fraction of probability enclosed by the sampled region
+// of ExtentOf.
+const DefaultEnclosed = 0.999
+
+// ExtentOf returns the default extent of the given wave function; the radius of
+// the sampled region encloses the fraction DefaultEnclosed of the probability
+// (see wave.EnclosingRadius), and thus scales with n^2/Z. The resolution is
+// chosen from the default target number of points.
+func ExtentOf(w wave.Wavefunction) (Extent, error) {
+	max, err := wave.EnclosingRadius(w, DefaultEnclosed)
+	if err != nil {
+		return Extent{}, errors.WithStack(err)
+	}
+	return Extent{Max: max}, nil
+}
+
+// Default number of samples per axis of the Cartesian sampler and radial
+// samples of the spherical sampler, when no target number of points is given.
+const (
+	DefaultCartesianGrid = 401
+	DefaultSphericGrid   = 1300
+)
+
+// Cartesian returns the step, half side length and number of grid points per
+// axis of the cube sampled by the Cartesian sampler. If no number of grid
+// points per axis is given, it is chosen such that the grid has approximately
+// the target number of points.
+//
+// An error is returned if the extent is invalid.
+func (ext Extent) Cartesian() (step, max float64, n int, err error) {
+	if err := ext.check(); err != nil {
+		return 0, 0, 0, errors.WithStack(err)
+	}
+	n = ext.Grid
+	if n <= 0 {
+		n = DefaultCartesianGrid
+		if ext.Points > 0 {
+			n = int(math.Round(math.Cbrt(float64(ext.Points))))
+		}
+	}
+	if n < 2 {
+		n = 2
+	}
+	max = ext.Max
+	step = 2 * max / float64(n-1)
+	return step, max, n, nil
+}
+
+// Spheric returns the radial step, maximum radius and number of radial samples
+// of the spherical sampler. If no number of radial samples is given, it is
+// chosen such that the spherical grid has approximately the target number of
+// points.
+//
+// An error is returned if the extent or its sampling scheme is invalid.
+func (ext Extent) Spheric() (step, max float64, n int, err error) {
+	if err := ext.check(); err != nil {
+		return 0, 0, 0, errors.WithStack(err)
+	}
+	n = ext.Grid
+	if n <= 0 {
+		n = DefaultSphericGrid
+		if ext.Points > 0 {
+			dirs, err := ext.Sphere.directions(ext.Directions)
+			if err != nil {
+				return 0, 0, 0, errors.WithStack(err)
+			}
+			n = int(math.Round(float64(ext.Points) / float64(len(dirs))))
+		}
+	}
+	if n < 2 {
+		n = 2
+	}
+	max = ext.Max
+	step = max / float64(n)
+	return step, max, n, nil
+}
+
+// check returns an error if the extent is invalid.
+func (ext Extent) check() error {
+	if !(ext.Max > 0) {
+		return errors.Errorf("invalid sampling extent; expected Max > 0, got %g", ext.Max)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package sample
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+
+	"github.com/mewmew/orbitals/orb"
+	"github.com/mewmew/orbitals/wave"
+	"github.com/pkg/errors"
+)
+
+// Monte Carlo sampling draws electron positions distributed according to the
+// probability density |psi|^2, so that the density of points is proportional to
+// the probability of electron occurence ("dot density" pictures).
+
+// Rejection returns a 3D-model of npoints electron positions drawn by rejection
+// sampling from the probability density |psi|^2 of the specified complex-valued
+// wave function, psi. The random number generator is seeded with the given
+// seed, for reproducibility.
+//
+// Candidate positions are drawn uniformly from the bounding box of the orbital
+// within the given extent (see scanDensity), and accepted with probability
+// |psi|^2/bound, where the bound is estimated from a coarse grid. Should a
+// candidate exceed the bound, the bound is raised and sampling restarts, as the
+// positions accepted so far would be over-weighted relative to subsequent ones.
+//
+// An error is returned if the extent is invalid, or if the probability density
+// vanishes on the coarse grid.
+func Rejection(ext Extent, Psi wave.ComplexPsiFunc, npoints int, seed int64) ([]orb.CartesianPoint, error) {
+	if err := ext.check(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	scan := scanDensity(Psi, ext.Max)
+	if scan.max == 0 {
+		return nil, errors.Errorf("invalid probability density; expected non-zero density within extent %g", ext.Max)
+	}
+	// Use a safety factor for the upper bound of |psi|^2, to account for peaks
+	// between grid points.
+	bound := 2.0 * scan.max
+	r := rand.New(rand.NewSource(seed))
+	pts := make([]orb.CartesianPoint, 0, npoints)
+	for len(pts) < npoints {
+		x := scan.lo[0] + r.Float64()*(scan.hi[0]-scan.lo[0])
+		y := scan.lo[1] + r.Float64()*(scan.hi[1]-scan.lo[1])
+		z := scan.lo[2] + r.Float64()*(scan.hi[2]-scan.lo[2])
+		psi := psiAt(Psi, x, y, z)
+		density := math.Pow(cmplx.Abs(psi), 2)
+		if density > bound {
+			// The bound was underestimated; raise it and discard the positions
+			// accepted with the underestimated bound.
+			bound = 2.0 * density
+			pts = pts[:0]
+			continue
+		}
+		if r.Float64()*bound >= density {
+			continue
+		}
+		pts = append(pts, monteCarloPoint(x, y, z, psi, npoints))
+	}
+	return pts, nil
+}
+
+// Metropolis returns a 3D-model of npoints electron positions drawn by the
+// Metropolis–Hastings algorithm from the probability density |psi|^2 of the
+// specified complex-valued wave function, psi. The random number generator is
+// seeded with the given seed, for reproducibility.
+//
+// The random walk starts at the position of maximum density on a coarse grid
+// within the given extent, uses Gaussian proposals, discards the first
+// metropolisBurnIn steps and keeps every metropolisThinning:th step thereafter
+// to reduce autocorrelation.
+//
+// An error is returned if the extent is invalid, or if the probability density
+// vanishes on the coarse grid.
+func Metropolis(ext Extent, Psi wave.ComplexPsiFunc, npoints int, seed int64) ([]orb.CartesianPoint, error) {
+	if err := ext.check(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	scan := scanDensity(Psi, ext.Max)
+	if scan.max == 0 {
+		return nil, errors.Errorf("invalid probability density; expected non-zero density within extent %g", ext.Max)
+	}
+	// Proposal step length; a tenth of the bounding box, which is large enough
+	// to cross the nodal surfaces between lobes.
+	sigma := ((scan.hi[0] - scan.lo[0]) + (scan.hi[1] - scan.lo[1]) + (scan.hi[2] - scan.lo[2])) / 30.0
+	r := rand.New(rand.NewSource(seed))
+	x, y, z := scan.x, scan.y, scan.z
+	psi := psiAt(Psi, x, y, z)
+	density := math.Pow(cmplx.Abs(psi), 2)
+	pts := make([]orb.CartesianPoint, 0, npoints)
+	for i := 0; len(pts) < npoints; i++ {
+		nx := x + r.NormFloat64()*sigma
+		ny := y + r.NormFloat64()*sigma
+		nz := z + r.NormFloat64()*sigma
+		npsi := psiAt(Psi, nx, ny, nz)
+		ndensity := math.Pow(cmplx.Abs(npsi), 2)
+		// Accept with probability min(1, ndensity/density); the proposal
+		// distribution is symmetric.
+		if ndensity >= density || r.Float64()*density < ndensity {
+			x, y, z, psi, density = nx, ny, nz, npsi, ndensity
+		}
+		if i < metropolisBurnIn || (i-metropolisBurnIn)%metropolisThinning != 0 {
+			continue
+		}
+		pts = append(pts, monteCarloPoint(x, y, z, psi, npoints))
+	}
+	return pts, nil
+}
+
+const (
+	// Number of initial steps of the Metropolis–Hastings random walk to discard.
+	metropolisBurnIn = 1000
+	// Keep every n:th step of the Metropolis–Hastings random walk.
+	metropolisThinning = 10
+)
+
+// densityScan is the result of scanning the probability density |psi|^2 on a
+// coarse grid.
+type densityScan struct {
+	// Position of maximum density.
+	x, y, z float64
+	// Maximum density.
+	max float64
+	// Bounding box, per axis, enclosing all but a fraction tailMass of the
+	// probability on the grid.
+	lo, hi [3]float64
+}
+
+// tailMass is the fraction of probability left outside of the bounding box of
+// a density scan.
+const tailMass = 1.0e-4
+
+// scanDensity scans the probability density |psi|^2 on a coarse grid within the
+// cube [-extent, extent]^3.
+func scanDensity(Psi wave.ComplexPsiFunc, extent float64) densityScan {
+	// Use an odd number of grid points per axis to include the origin.
+	const n = 65
+	step := 2.0 * extent / (n - 1)
+	coord := func(i int) float64 {
+		return -extent + float64(i)*step
+	}
+	// Marginal probability per axis.
+	var marginals [3][n]float64
+	var scan densityScan
+	total := 0.0
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			for k := 0; k < n; k++ {
+				x, y, z := coord(i), coord(j), coord(k)
+				density := math.Pow(cmplx.Abs(psiAt(Psi, x, y, z)), 2)
+				if density > scan.max {
+					scan.x, scan.y, scan.z, scan.max = x, y, z, density
+				}
+				marginals[0][i] += density
+				marginals[1][j] += density
+				marginals[2][k] += density
+				total += density
+			}
+		}
+	}
+	// Locate bounding box, padded by one grid step.
+	for axis := range marginals {
+		lo, hi := 0, n-1
+		for mass := 0.0; lo < hi && mass+marginals[axis][lo] < total*tailMass/2; lo++ {
+			mass += marginals[axis][lo]
+		}
+		for mass := 0.0; hi > lo && mass+marginals[axis][hi] < total*tailMass/2; hi-- {
+			mass += marginals[axis][hi]
+		}
+		scan.lo[axis] = math.Max(coord(lo)-step, -extent)
+		scan.hi[axis] = math.Min(coord(hi)+step, extent)
+	}
+	return scan
+}
+
+// psiAt returns psi at the given Cartesian (x, y, z)-coordinate.
+func psiAt(Psi wave.ComplexPsiFunc, x, y, z float64) complex128 {
+	rho, theta, phi := wave.SphericalFromCartesian(x, y, z)
+	return Psi(rho, theta, phi)
+}
+
+// monteCarloPoint returns the point of an electron position drawn by Monte
+// Carlo sampling, with Cartesian coordinates in picometer. Each of the npoints
+// positions carries the same probability.
+func monteCarloPoint(x, y, z float64, psi complex128, npoints int) orb.CartesianPoint {
+	return orb.CartesianPoint{
+		X:     x / wave.Picometer,
+		Y:     y / wave.Picometer,
+		Z:     z / wave.Picometer,
+		Prob:  1.0 / float64(npoints),
+		Amp:   cmplx.Abs(psi),
+		Phase: cmplx.Phase(psi),
+	}
+}
@@ -0,0 +1,68 @@
+package sample
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/orbitals/orb"
+	"github.com/mewmew/orbitals/wave"
+)
+
+func TestMonteCarlo(t *testing.T) {
+	samplers := []struct {
+		name   string
+		sample func(ext Extent, Psi wave.ComplexPsiFunc, npoints int, seed int64) ([]orb.CartesianPoint, error)
+	}{
+		{name: "Rejection", sample: Rejection},
+		{name: "Metropolis", sample: Metropolis},
+	}
+	w, err := wave.NewHydrogenic(wave.RealBasis, 1, 1, 0, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ext, err := ExtentOf(w)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, s := range samplers {
+		// Reproducible for a given seed.
+		const npoints = 500
+		var runs [3][]orb.CartesianPoint
+		for i, seed := range []int64{1, 1, 2} {
+			pts, err := s.sample(ext, w.Psi, npoints, seed)
+			if err != nil {
+				t.Fatalf("%s: unable to sample; %+v", s.name, err)
+			}
+			if len(pts) != npoints {
+				t.Errorf("%s: number of points mismatch; expected %d, got %d", s.name, npoints, len(pts))
+			}
+			runs[i] = pts
+		}
+		if !reflect.DeepEqual(runs[0], runs[1]) {
+			t.Errorf("%s: output mismatch between runs with the same seed", s.name)
+		}
+		if reflect.DeepEqual(runs[0], runs[2]) {
+			t.Errorf("%s: identical output of runs with different seeds", s.name)
+		}
+		// Expectation value of r of the 1s-orbital.
+		//
+		//    <r> = a/2 (3n^2 - l(l+1)) = 3/2 a_0
+		pts, err := s.sample(ext, w.Psi, 10000, 1)
+		if err != nil {
+			t.Fatalf("%s: unable to sample; %+v", s.name, err)
+		}
+		sum := 0.0
+		for _, pt := range pts {
+			x, y, z := pt.X*wave.Picometer, pt.Y*wave.Picometer, pt.Z*wave.Picometer
+			sum += math.Sqrt(x*x + y*y + z*z)
+		}
+		want := 1.5 * wave.BohrRadius
+		got := sum / float64(len(pts))
+		// Standard error of the mean is 0.87 a_0/√N, larger for the correlated
+		// points of Metropolis-Hastings.
+		if math.Abs(got-want) > 0.03*want {
+			t.Errorf("%s: <r> mismatch; expected %.3f a_0, got %.3f a_0", s.name, want, got)
+		}
+	}
+}
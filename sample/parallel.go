@@ -0,0 +1,60 @@
+package sample
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// numWorkers returns the number of concurrent workers of the grid samplers
+// within the given extent (see Extent.Workers).
+func numWorkers(ext Extent) int {
+	if ext.Workers > 0 {
+		return ext.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// forSlabs evaluates the n slabs of a grid (e.g. the yz-planes of a Cartesian
+// grid) concurrently, using the workers of the given extent, and emits the
+// evaluated slabs in order. Slabs are evaluated in batches of one slab per
+// worker, and the slabs of a batch are emitted once the full batch has been
+// evaluated; thus the output order is independent of the number of workers, and
+// at most one batch of slabs is held in memory at once.
+//
+// The buffers of the w slabs of a batch are allocated by alloc(w), where w is
+// the number of workers. The i:th slab is evaluated by eval(i, slot) and
+// emitted by emit(i, slot), where slot in [0, w) identifies the buffer of the
+// slab within its batch. Errors returned by emit stop the iteration.
+func forSlabs(ext Extent, n int, alloc func(w int), eval func(i, slot int), emit func(i, slot int) error) error {
+	w := numWorkers(ext)
+	if w > n {
+		w = n
+	}
+	if w < 1 {
+		w = 1
+	}
+	alloc(w)
+	for start := 0; start < n; start += w {
+		end := start + w
+		if end > n {
+			end = n
+		}
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				eval(i, i-start)
+			}(i)
+		}
+		wg.Wait()
+		for i := start; i < end; i++ {
+			if err := emit(i, i-start); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
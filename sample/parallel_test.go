@@ -0,0 +1,93 @@
+package sample
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/orbitals/orb"
+	"github.com/mewmew/orbitals/prune"
+	"github.com/mewmew/orbitals/wave"
+)
+
+func TestWorkers(t *testing.T) {
+	w, err := wave.NewHydrogenic(wave.ComplexBasis, 1, 4, 3, 2)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ext, err := ExtentOf(w)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ext.Grid = 23
+	ext.Sphere = FibonacciSphere
+	ext.Directions = 500
+	// Output of each sampler, for the given extent.
+	samplers := []struct {
+		name   string
+		sample func(ext Extent) (interface{}, error)
+	}{
+		{
+			name: "Cartesian",
+			sample: func(ext Extent) (interface{}, error) {
+				return Cartesian(SignedMode, ext, w.Psi)
+			},
+		},
+		{
+			name: "Spheric",
+			sample: func(ext Extent) (interface{}, error) {
+				return Spheric(DensityMode, ext, wave.Rays(w.Psi))
+			},
+		},
+		{
+			name: "CartesianGrid",
+			sample: func(ext Extent) (interface{}, error) {
+				g, err := CartesianGrid(DensityMode, ext, w.Psi)
+				if err != nil {
+					return nil, err
+				}
+				return g.Vals, nil
+			},
+		},
+		{
+			name: "CartesianStream",
+			sample: func(ext Extent) (interface{}, error) {
+				var pts []orb.CartesianPoint
+				err := CartesianStream(DensityMode, ext, w.Psi, prune.Mass(0.9), func(pt orb.CartesianPoint) error {
+					pts = append(pts, pt)
+					return nil
+				})
+				return pts, err
+			},
+		},
+		{
+			name: "SphericStream",
+			sample: func(ext Extent) (interface{}, error) {
+				var pts []orb.CartesianPoint
+				err := SphericStream(RadialMode, ext, wave.Rays(w.Psi), prune.TopK(1000), func(pt orb.CartesianPoint) error {
+					pts = append(pts, pt)
+					return nil
+				})
+				return pts, err
+			},
+		},
+	}
+	for _, s := range samplers {
+		ext.Workers = 1
+		want, err := s.sample(ext)
+		if err != nil {
+			t.Errorf("%s: unable to sample; %v", s.name, err)
+			continue
+		}
+		for _, workers := range []int{2, 3, 7, 64} {
+			ext.Workers = workers
+			got, err := s.sample(ext)
+			if err != nil {
+				t.Errorf("%s: unable to sample with %d workers; %v", s.name, workers, err)
+				continue
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: output mismatch between 1 and %d workers", s.name, workers)
+			}
+		}
+	}
+}
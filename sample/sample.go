@@ -0,0 +1,350 @@
+// Package sample implements sampling of the probability distribution of wave
+// functions, for generating 3D-models of electron orbitals.
+package sample
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/mewmew/orbitals/orb"
+	"github.com/mewmew/orbitals/wave"
+	"github.com/pkg/errors"
+)
+
+// Mode specifies the quantity sampled as probability of the points of a
+// 3D-model.
+type Mode uint8
+
+// Sampling modes.
+const (
+	// DensityMode samples the probability density |psi|^2. On the uniform
+	// Cartesian grid, this is the probability of each voxel.
+	DensityMode Mode = iota
+	// RadialMode samples the radial probability 4πr^2 |psi|^2 (see RadialProb).
+	RadialMode
+	// SignedMode samples the signed probability density Re(psi) |psi|, which is
+	// positive for positive lobes and negative for negative lobes.
+	SignedMode
+)
+
+// String returns the string representation of the sampling mode.
+func (mode Mode) String() string {
+	switch mode {
+	case DensityMode:
+		return "density"
+	case RadialMode:
+		return "radial"
+	case SignedMode:
+		return "signed"
+	}
+	return fmt.Sprintf("Mode(%d)", uint8(mode))
+}
+
+// probFunc is a function computing the (unnormalized) probability of a
+// sampling mode, based on the radius, r, psi and its amplitude, amp = |psi|.
+type probFunc func(r float64, psi complex128, amp float64) float64
+
+// sampleProb returns the function computing the probability of the given
+// sampling mode.
+func sampleProb(mode Mode) (probFunc, error) {
+	switch mode {
+	case DensityMode:
+		return func(r float64, psi complex128, amp float64) float64 {
+			return amp * amp
+		}, nil
+	case RadialMode:
+		return func(r float64, psi complex128, amp float64) float64 {
+			return wave.RadialProb(r, amp)
+		}, nil
+	case SignedMode:
+		return func(r float64, psi complex128, amp float64) float64 {
+			return real(psi) * amp
+		}, nil
+	}
+	return nil, errors.Errorf("support for sampling mode %v not yet implemented", mode)
+}
+
+// Spheric returns a 3D-model visualizing the probability distribution of the
+// electron orbital with the specified complex-valued wave function, psi,
+// evaluated along rays from the origin, sampled on a spherical grid within the
+// given extent (see Extent.Spheric). The spherical grid has radial samples in
+// uniform steps along each of the directions sampled by the scheme of the
+// extent (see Sphere). The probability of each point is sampled in the given
+// mode, and the amplitude and phase of psi are recorded for each point.
+//
+// The wave function is evaluated along the ray of each direction; thus the
+// angular part of separable wave functions may be evaluated once per direction
+// (see wave.Tabulated.Ray), while other wave functions are evaluated at each
+// point (see wave.Rays).
+//
+// Probabilities are weighted by the volume element r^2 dr dΩ of each grid
+// point, where dΩ is the solid angle represented by its direction (e.g. sin θ
+// dθ dφ for the uniform grid); thus in density mode, the probability of a
+// point is the probability of its grid cell. In radial mode, which already
+// includes the factor r^2, probabilities are weighted by the solid angle only.
+//
+// An error is returned if the sampling mode or extent is invalid.
+func Spheric(mode Mode, ext Extent, Ray wave.RayFunc) ([]orb.SphericalPoint, error) {
+	_, _, grid, err := ext.Spheric()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	dirs, err := ext.Sphere.directions(ext.Directions)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	pts := make([]orb.SphericalPoint, 0, grid*len(dirs))
+	err = visitSpheric(mode, ext, Ray, func(pt orb.SphericalPoint) error {
+		pts = append(pts, pt)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Normalize probability, such that the sum of absolute probabilities is 1.
+	totalProb := 0.0
+	for i := range pts {
+		totalProb += math.Abs(pts[i].Prob)
+	}
+	if totalProb != 0 {
+		for i := range pts {
+			pts[i].Prob /= totalProb
+		}
+	}
+	return pts, nil
+}
+
+// visitSpheric invokes fn for each point of the spherical grid of Spheric, in
+// order, with unnormalized probabilities. The grid is evaluated concurrently in
+// slabs of sphericSlab directions (see Extent.Workers). Errors returned by fn
+// stop the iteration.
+func visitSpheric(mode Mode, ext Extent, Ray wave.RayFunc, fn func(pt orb.SphericalPoint) error) error {
+	prob, err := sampleProb(mode)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	step, _, grid, err := ext.Spheric()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	dirs, err := ext.Sphere.directions(ext.Directions)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	nslabs := (len(dirs) + sphericSlab - 1) / sphericSlab
+	var bufs [][]orb.SphericalPoint
+	alloc := func(w int) {
+		bufs = make([][]orb.SphericalPoint, w)
+	}
+	eval := func(s, slot int) {
+		start, end := s*sphericSlab, (s+1)*sphericSlab
+		if end > len(dirs) {
+			end = len(dirs)
+		}
+		buf := bufs[slot][:0]
+		for _, dir := range dirs[start:end] {
+			psiAlong := Ray(dir.theta, dir.phi)
+			for i := 0; i < grid; i++ {
+				rho := float64(i) * step
+				psi := psiAlong(rho)
+				amp := cmplx.Abs(psi)
+				// Weight by the volume element r^2 dr dΩ of the grid point; the
+				// radial probability of RadialMode already includes r^2 (see
+				// RadialProb).
+				weight := dir.solid
+				if mode != RadialMode {
+					weight *= rho * rho * step
+				}
+				pt := orb.SphericalPoint{
+					Rho:   rho,
+					Theta: dir.theta,
+					Phi:   dir.phi,
+					Prob:  weight * prob(rho, psi, amp),
+					Amp:   amp,
+					Phase: cmplx.Phase(psi),
+				}
+				buf = append(buf, pt)
+			}
+		}
+		bufs[slot] = buf
+	}
+	emit := func(s, slot int) error {
+		for _, pt := range bufs[slot] {
+			if err := fn(pt); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		return nil
+	}
+	return forSlabs(ext, nslabs, alloc, eval, emit)
+}
+
+// sphericSlab is the number of directions per slab of the spherical grid.
+const sphericSlab = 90
+
+// Cartesian returns a 3D-model visualizing the probability distribution of the
+// electron orbital with the specified complex-valued wave function, psi,
+// sampled on a uniform Cartesian grid within the given extent (see
+// Extent.Cartesian). The probability of each point is sampled in the given
+// mode, and the amplitude and phase of psi are recorded for each point. The
+// coordinates of points are in picometer.
+//
+// An error is returned if the sampling mode or extent is invalid.
+func Cartesian(mode Mode, ext Extent, Psi wave.ComplexPsiFunc) ([]orb.CartesianPoint, error) {
+	_, _, n, err := ext.Cartesian()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	pts := make([]orb.CartesianPoint, 0, n*n*n)
+	err = visitCartesian(mode, ext, Psi, func(pt orb.CartesianPoint) error {
+		pts = append(pts, pt)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Normalize probability, such that the sum of absolute probabilities is 1.
+	totalProb := 0.0
+	for i := range pts {
+		totalProb += math.Abs(pts[i].Prob)
+	}
+	if totalProb != 0 {
+		for i := range pts {
+			pts[i].Prob /= totalProb
+		}
+	}
+	return pts, nil
+}
+
+// visitCartesian invokes fn for each point of the Cartesian grid of Cartesian,
+// in order, with unnormalized probabilities. The grid is evaluated concurrently
+// in slabs of constant x (see Extent.Workers). Errors returned by fn stop the
+// iteration.
+func visitCartesian(mode Mode, ext Extent, Psi wave.ComplexPsiFunc, fn func(pt orb.CartesianPoint) error) error {
+	prob, err := sampleProb(mode)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	step, max, n, err := ext.Cartesian()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var bufs [][]orb.CartesianPoint
+	alloc := func(w int) {
+		bufs = make([][]orb.CartesianPoint, w)
+		for slot := range bufs {
+			bufs[slot] = make([]orb.CartesianPoint, n*n)
+		}
+	}
+	eval := func(i, slot int) {
+		buf := bufs[slot]
+		x := -max + float64(i)*step
+		for j := 0; j < n; j++ {
+			y := -max + float64(j)*step
+			for k := 0; k < n; k++ {
+				z := -max + float64(k)*step
+				rho, theta, phi := wave.SphericalFromCartesian(x, y, z)
+				psi := Psi(rho, theta, phi)
+				amp := cmplx.Abs(psi)
+				buf[j*n+k] = orb.CartesianPoint{
+					X:     x / wave.Picometer,
+					Y:     y / wave.Picometer,
+					Z:     z / wave.Picometer,
+					Prob:  prob(rho, psi, amp),
+					Amp:   amp,
+					Phase: cmplx.Phase(psi),
+				}
+			}
+		}
+	}
+	emit := func(i, slot int) error {
+		for _, pt := range bufs[slot] {
+			if err := fn(pt); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		return nil
+	}
+	return forSlabs(ext, n, alloc, eval, emit)
+}
+
+// Grid is a uniform Cartesian grid of sampled values.
+type Grid struct {
+	// Number of grid points per axis.
+	N int
+	// Values indexed by (i*N+j)*N+k, for the grid point (i, j, k) along the (x,
+	// y, z)-axes.
+	Vals []float64
+}
+
+// At returns the value at the grid point (i, j, k), or the given padding value
+// if the grid point lies outside of the grid.
+func (g *Grid) At(i, j, k int, pad float64) float64 {
+	if i < 0 || j < 0 || k < 0 || i >= g.N || j >= g.N || k >= g.N {
+		return pad
+	}
+	return g.Vals[(i*g.N+j)*g.N+k]
+}
+
+// CartesianGrid returns a uniform grid of the probability of the electron
+// orbital with the specified complex-valued wave function, psi, sampled in the
+// given mode within the given extent (see Extent.Cartesian). The grid covers the
+// cube sampled by Cartesian, and the probabilities are normalized in the same
+// way. The grid is evaluated concurrently in slabs of constant x (see
+// Extent.Workers).
+//
+// An error is returned if the sampling mode or extent is invalid.
+func CartesianGrid(mode Mode, ext Extent, Psi wave.ComplexPsiFunc) (*Grid, error) {
+	prob, err := sampleProb(mode)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	step, max, n, err := ext.Cartesian()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	g := &Grid{
+		N:    n,
+		Vals: make([]float64, n*n*n),
+	}
+	// Sum of absolute probabilities, per slab and in total; summed in order to
+	// be independent of the number of workers.
+	var (
+		sums  []float64
+		total = 0.0
+	)
+	alloc := func(w int) {
+		sums = make([]float64, w)
+	}
+	eval := func(i, slot int) {
+		x := -max + float64(i)*step
+		sum := 0.0
+		for j := 0; j < n; j++ {
+			y := -max + float64(j)*step
+			for k := 0; k < n; k++ {
+				z := -max + float64(k)*step
+				rho, theta, phi := wave.SphericalFromCartesian(x, y, z)
+				psi := Psi(rho, theta, phi)
+				p := prob(rho, psi, cmplx.Abs(psi))
+				g.Vals[(i*n+j)*n+k] = p
+				sum += math.Abs(p)
+			}
+		}
+		sums[slot] = sum
+	}
+	emit := func(i, slot int) error {
+		total += sums[slot]
+		return nil
+	}
+	if err := forSlabs(ext, n, alloc, eval, emit); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Normalize probability, such that the sum of absolute probabilities is 1.
+	if total != 0 {
+		for i := range g.Vals {
+			g.Vals[i] /= total
+		}
+	}
+	return g, nil
+}
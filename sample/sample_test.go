@@ -0,0 +1,333 @@
+package sample
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/mewmew/orbitals/orb"
+	"github.com/mewmew/orbitals/prune"
+	"github.com/mewmew/orbitals/wave"
+)
+
+func TestCartesianPicometer(t *testing.T) {
+	golden := []struct {
+		Z, n, l, m int
+	}{
+		{Z: 1, n: 1, l: 0, m: 0},
+		{Z: 2, n: 1, l: 0, m: 0},
+		{Z: 1, n: 2, l: 1, m: 0},
+		{Z: 3, n: 2, l: 1, m: 0},
+		{Z: 2, n: 3, l: 2, m: 1},
+	}
+	for _, g := range golden {
+		w, err := wave.NewHydrogenic(wave.RealBasis, g.Z, g.n, g.l, g.m)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext, err := ExtentOf(w)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext.Grid = 61
+		pts, err := Cartesian(DensityMode, ext, w.Psi)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		// Expectation value of r^2 in picometer^2, which scales by 1/Z^2.
+		//
+		//    <r^2> = a^2 n^2/2 (5n^2 + 1 - 3l(l+1)), where a = a_0/Z
+		got := meanSquareRadius(pts)
+		a := wave.BohrRadius / float64(g.Z) / wave.Picometer
+		n, l := float64(g.n), float64(g.l)
+		want := a * a * n * n / 2 * (5*n*n + 1 - 3*l*(l+1))
+		if math.Abs(got-want) > 0.02*want {
+			t.Errorf("%s (Z=%d): <r^2> mismatch; expected %.0f pm^2, got %.0f pm^2", w.Label(), g.Z, want, got)
+		}
+	}
+}
+
+// meanSquareRadius returns the expectation value of r^2 of the given points,
+// weighted by absolute probability.
+func meanSquareRadius(pts []orb.CartesianPoint) float64 {
+	sum, total := 0.0, 0.0
+	for _, pt := range pts {
+		x, y, z := pt.X, pt.Y, pt.Z
+		sum += math.Abs(pt.Prob) * (x*x + y*y + z*z)
+		total += math.Abs(pt.Prob)
+	}
+	return sum / total
+}
+
+func TestCartesianUnique(t *testing.T) {
+	// High nuclear charges shrink the auto-scaled extent, and thus the grid
+	// step, well below one picometer.
+	for _, Z := range []int{10, 100} {
+		w, err := wave.NewHydrogenic(wave.RealBasis, Z, 1, 0, 0)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext, err := ExtentOf(w)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext.Grid = 41
+		pts, err := Cartesian(DensityMode, ext, w.Psi)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		seen := make(map[[3]float64]bool)
+		for _, pt := range pts {
+			seen[[3]float64{pt.X, pt.Y, pt.Z}] = true
+		}
+		if len(seen) != len(pts) {
+			t.Errorf("%s (Z=%d): duplicate points; expected %d unique points, got %d", w.Label(), Z, len(pts), len(seen))
+		}
+	}
+}
+
+func TestCartesianPhase(t *testing.T) {
+	golden := []struct {
+		basis      wave.Basis
+		Z, n, l, m int
+	}{
+		{basis: wave.RealBasis, Z: 1, n: 2, l: 1, m: 1},
+		{basis: wave.RealBasis, Z: 2, n: 3, l: 2, m: -2},
+		{basis: wave.ComplexBasis, Z: 1, n: 2, l: 1, m: 1},
+		{basis: wave.ComplexBasis, Z: 1, n: 3, l: 2, m: -2},
+		{basis: wave.ComplexBasis, Z: 3, n: 4, l: 3, m: 3},
+	}
+	for _, g := range golden {
+		w, err := wave.NewHydrogenic(g.basis, g.Z, g.n, g.l, g.m)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext, err := ExtentOf(w)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext.Grid = 21
+		pts, err := Cartesian(DensityMode, ext, w.Psi)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		// Amplitude and phase of each point recover psi at its coordinate,
+		// converted from picometer.
+		for _, pt := range pts {
+			x, y, z := pt.X*wave.Picometer, pt.Y*wave.Picometer, pt.Z*wave.Picometer
+			want := w.Psi(wave.SphericalFromCartesian(x, y, z))
+			got := cmplx.Rect(pt.Amp, pt.Phase)
+			if tol := 1e-12 * math.Max(1, cmplx.Abs(want)); cmplx.Abs(got-want) > tol || math.Abs(pt.Signed()-real(want)) > tol {
+				t.Errorf("%s: psi mismatch at (%g, %g, %g) pm; expected %g, got %g", w.Label(), pt.X, pt.Y, pt.Z, want, got)
+				break
+			}
+			if g.basis == wave.RealBasis && pt.Phase != 0 && pt.Phase != math.Pi {
+				t.Errorf("%s: phase mismatch at (%g, %g, %g) pm; expected 0 or π, got %g", w.Label(), pt.X, pt.Y, pt.Z, pt.Phase)
+				break
+			}
+		}
+	}
+}
+
+func TestSampleModes(t *testing.T) {
+	golden := []struct {
+		Z, n, l, m int
+	}{
+		{Z: 1, n: 1, l: 0, m: 0},
+		{Z: 2, n: 2, l: 1, m: 0},
+		{Z: 1, n: 3, l: 2, m: 0},
+	}
+	for _, g := range golden {
+		w, err := wave.NewHydrogenic(wave.RealBasis, g.Z, g.n, g.l, g.m)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext, err := ExtentOf(w)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		// The probability density |psi|^2 integrates to 1; the sampled region
+		// encloses the fraction DefaultEnclosed of the probability.
+		const want = DefaultEnclosed
+		// Cartesian grid; |psi|^2 is summed over the volume elements dV = step^3,
+		// as is |Re(psi) psi| of real wave functions. The radial probability is the
+		// density weighted by 4πr^2.
+		ext.Grid = 61
+		step, _, _, err := ext.Cartesian()
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		sums := make(map[Mode]float64)
+		var pts [3][]orb.CartesianPoint
+		for _, mode := range []Mode{DensityMode, RadialMode, SignedMode} {
+			err := visitCartesian(mode, ext, w.Psi, func(pt orb.CartesianPoint) error {
+				sums[mode] += math.Abs(pt.Prob) * step * step * step
+				pts[mode] = append(pts[mode], pt)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+		}
+		for _, mode := range []Mode{DensityMode, SignedMode} {
+			if got := sums[mode]; math.Abs(got-want) > 0.01 {
+				t.Errorf("%s (Cartesian, %v mode): sum of |psi|^2 dV mismatch; expected %.3f, got %.3f", w.Label(), mode, want, got)
+			}
+		}
+		for i, pt := range pts[RadialMode] {
+			density, signed := pts[DensityMode][i], pts[SignedMode][i]
+			x, y, z := pt.X*wave.Picometer, pt.Y*wave.Picometer, pt.Z*wave.Picometer
+			r2 := x*x + y*y + z*z
+			if want := 4 * math.Pi * r2 * density.Prob; math.Abs(pt.Prob-want) > 1e-9*math.Max(want, 1e-9) {
+				t.Errorf("%s (Cartesian, radial mode): probability mismatch at (%g, %g, %g) pm; expected 4πr^2 |psi|^2 = %g, got %g", w.Label(), pt.X, pt.Y, pt.Z, want, pt.Prob)
+				break
+			}
+			if want := math.Copysign(density.Prob, signed.Signed()); signed.Prob != want {
+				t.Errorf("%s (Cartesian, signed mode): probability mismatch at (%g, %g, %g) pm; expected %g, got %g", w.Label(), pt.X, pt.Y, pt.Z, want, signed.Prob)
+				break
+			}
+		}
+		// Spherical grid; points are weighted by the volume element r^2 dr dΩ in
+		// density mode, and by dΩ in radial mode, which integrates to 4π over dr.
+		ext.Grid = 200
+		ext.Directions = 1000
+		step, _, _, err = ext.Spheric()
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		for _, mode := range []Mode{DensityMode, RadialMode} {
+			sum := 0.0
+			err := visitSpheric(mode, ext, wave.Rays(w.Psi), func(pt orb.SphericalPoint) error {
+				sum += pt.Prob
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if mode == RadialMode {
+				sum *= step / (4 * math.Pi)
+			}
+			if math.Abs(sum-want) > 0.01 {
+				t.Errorf("%s (spherical, %v mode): total probability mismatch; expected %.3f, got %.3f", w.Label(), mode, want, sum)
+			}
+		}
+	}
+}
+
+// benchmarkOrbitals lists the wave functions of the sampler benchmarks.
+func benchmarkOrbitals(b *testing.B) []wave.Wavefunction {
+	var ws []wave.Wavefunction
+	for _, nlm := range [][3]int{{1, 0, 0}, {3, 2, 0}, {5, 3, 1}} {
+		w, err := wave.NewHydrogenic(wave.RealBasis, 1, nlm[0], nlm[1], nlm[2])
+		if err != nil {
+			b.Fatalf("%+v", err)
+		}
+		ws = append(ws, w)
+	}
+	hs, err := wave.Hybrids("sp3", 1)
+	if err != nil {
+		b.Fatalf("%+v", err)
+	}
+	return append(ws, hs[0])
+}
+
+func BenchmarkCartesian(b *testing.B) {
+	for _, w := range benchmarkOrbitals(b) {
+		ext, err := ExtentOf(w)
+		if err != nil {
+			b.Fatalf("%+v", err)
+		}
+		ext.Grid = 64
+		tab, err := wave.Tabulate(w, math.Sqrt(3)*ext.Max)
+		if err != nil {
+			b.Fatalf("%+v", err)
+		}
+		b.Run(w.Label()+"/direct", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Cartesian(DensityMode, ext, w.Psi)
+			}
+		})
+		b.Run(w.Label()+"/tabulated", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Cartesian(DensityMode, ext, tab.Psi)
+			}
+		})
+	}
+}
+
+func BenchmarkSpheric(b *testing.B) {
+	for _, w := range benchmarkOrbitals(b) {
+		ext, err := ExtentOf(w)
+		if err != nil {
+			b.Fatalf("%+v", err)
+		}
+		ext.Grid = 200
+		ext.Directions = 1000
+		tab, err := wave.Tabulate(w, ext.Max)
+		if err != nil {
+			b.Fatalf("%+v", err)
+		}
+		b.Run(w.Label()+"/direct", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Spheric(DensityMode, ext, wave.Rays(w.Psi))
+			}
+		})
+		b.Run(w.Label()+"/tabulated", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Spheric(DensityMode, ext, tab.Ray)
+			}
+		})
+	}
+}
+
+func TestSampleInvalid(t *testing.T) {
+	w, err := wave.NewHydrogenic(wave.RealBasis, 1, 2, 1, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	golden := []struct {
+		name string
+		mode Mode
+		ext  Extent
+	}{
+		{name: "Max = 0", mode: DensityMode, ext: Extent{Max: 0, Grid: 8}},
+		{name: "Max < 0", mode: DensityMode, ext: Extent{Max: -1, Grid: 8}},
+		{name: "Max = NaN", mode: DensityMode, ext: Extent{Max: math.NaN(), Grid: 8}},
+		{name: "unknown mode", mode: Mode(42), ext: Extent{Max: 10, Grid: 8}},
+		{name: "unknown sphere", mode: DensityMode, ext: Extent{Max: 10, Grid: 8, Sphere: Sphere(42)}},
+	}
+	// Each sampler, returning an error for invalid input.
+	samplers := []struct {
+		name   string
+		sample func(mode Mode, ext Extent) error
+	}{
+		{name: "Cartesian", sample: func(mode Mode, ext Extent) error {
+			_, err := Cartesian(mode, ext, w.Psi)
+			return err
+		}},
+		{name: "Spheric", sample: func(mode Mode, ext Extent) error {
+			_, err := Spheric(mode, ext, wave.Rays(w.Psi))
+			return err
+		}},
+		{name: "CartesianGrid", sample: func(mode Mode, ext Extent) error {
+			_, err := CartesianGrid(mode, ext, w.Psi)
+			return err
+		}},
+		{name: "SphericStream", sample: func(mode Mode, ext Extent) error {
+			return SphericStream(mode, ext, wave.Rays(w.Psi), prune.Abs(0), func(pt orb.CartesianPoint) error {
+				return nil
+			})
+		}},
+	}
+	for _, g := range golden {
+		for _, s := range samplers {
+			// Only the spherical sampler samples directions.
+			if g.ext.Sphere != UniformSphere && s.name != "Spheric" && s.name != "SphericStream" {
+				continue
+			}
+			if err := s.sample(g.mode, g.ext); err == nil {
+				t.Errorf("%s (%s): expected error, got nil", s.name, g.name)
+			}
+		}
+	}
+}
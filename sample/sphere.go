@@ -0,0 +1,170 @@
+package sample
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// Sphere is a scheme of sampling directions on the unit sphere, used by the
+// spherical sampler.
+type Sphere uint8
+
+// Sampling schemes of directions.
+const (
+	// UniformSphere samples directions on a grid of uniformly spaced inclinations
+	// and azimuths, in steps of 4° by default. The directions are denser near
+	// the poles.
+	UniformSphere Sphere = iota
+	// FibonacciSphere samples directions on the Fibonacci (golden spiral)
+	// lattice, with approximately equal area per direction.
+	//
+	// ref: https://arxiv.org/abs/0912.4540
+	FibonacciSphere
+	// HEALPixSphere samples directions at the centers of the pixels of the
+	// HEALPix tessellation (in ring order), with equal area per direction.
+	//
+	// ref: https://healpix.jpl.nasa.gov/pdf/intro.pdf
+	HEALPixSphere
+)
+
+// String returns the string representation of the sampling scheme.
+func (sphere Sphere) String() string {
+	switch sphere {
+	case UniformSphere:
+		return "uniform"
+	case FibonacciSphere:
+		return "fibonacci"
+	case HEALPixSphere:
+		return "healpix"
+	}
+	return fmt.Sprintf("Sphere(%d)", uint8(sphere))
+}
+
+// DefaultDirections is the default number of directions of the spherical
+// sampler; that of the uniform grid in steps of 4°.
+const DefaultDirections = 46 * 90
+
+// direction is a sampled direction on the unit sphere.
+type direction struct {
+	// Inclination and azimuth.
+	theta, phi float64
+	// Solid angle of the region of the unit sphere represented by the direction.
+	solid float64
+}
+
+// directions returns approximately n directions (0 for default) on the unit
+// sphere, sampled by the given scheme. The solid angles of the directions sum
+// to 4π.
+func (sphere Sphere) directions(n int) ([]direction, error) {
+	if n <= 0 {
+		n = DefaultDirections
+	}
+	switch sphere {
+	case UniformSphere:
+		return uniformDirections(n), nil
+	case FibonacciSphere:
+		return fibonacciDirections(n), nil
+	case HEALPixSphere:
+		return healpixDirections(n), nil
+	}
+	return nil, errors.Errorf("support for sampling scheme %v not yet implemented", sphere)
+}
+
+// uniformDirections returns the directions of a grid of uniformly spaced
+// inclinations and azimuths, with approximately n directions and twice as many
+// azimuths as inclination intervals. The inclinations include the poles, and
+// each direction represents the cell of the grid centered at the direction;
+// i.e. with solid angle
+//
+//    ΔΩ = Δφ (cos(θ - Δθ/2) - cos(θ + Δθ/2))
+//
+// with the cells at the poles clamped to the polar caps.
+func uniformDirections(n int) []direction {
+	// n = (m+1) 2m directions, for m inclination intervals.
+	m := int(math.Round((-1 + math.Sqrt(1+8*float64(n))) / 4))
+	if m < 1 {
+		m = 1
+	}
+	var (
+		dtheta = math.Pi / float64(m)
+		nphi   = 2 * m
+		dphi   = 2 * math.Pi / float64(nphi)
+	)
+	var dirs []direction
+	for i := 0; i <= m; i++ {
+		theta := float64(i) * dtheta
+		lo := math.Max(theta-dtheta/2, 0)
+		hi := math.Min(theta+dtheta/2, math.Pi)
+		solid := dphi * (math.Cos(lo) - math.Cos(hi))
+		for j := 0; j < nphi; j++ {
+			phi := float64(j) * dphi
+			dirs = append(dirs, direction{theta: theta, phi: phi, solid: solid})
+		}
+	}
+	return dirs
+}
+
+// fibonacciDirections returns the n directions of the Fibonacci lattice on the
+// unit sphere, each representing the solid angle 4π/n.
+//
+//    cos θ_i = 1 - (2i + 1)/n
+//    φ_i     = 2π i/Φ   (mod 2π)
+//
+// where Φ is the golden ratio.
+func fibonacciDirections(n int) []direction {
+	phiGolden := (1 + math.Sqrt(5)) / 2
+	solid := 4 * math.Pi / float64(n)
+	dirs := make([]direction, n)
+	for i := range dirs {
+		z := 1 - (2*float64(i)+1)/float64(n)
+		phi := math.Mod(2*math.Pi*float64(i)/phiGolden, 2*math.Pi)
+		dirs[i] = direction{theta: math.Acos(z), phi: phi, solid: solid}
+	}
+	return dirs
+}
+
+// healpixDirections returns the pixel centers of the HEALPix tessellation with
+// 12 N_side^2 pixels, for the resolution N_side giving approximately n
+// directions, in ring order from the north pole to the south pole. Each
+// direction represents the solid angle 4π/(12 N_side^2).
+func healpixDirections(n int) []direction {
+	nside := int(math.Round(math.Sqrt(float64(n) / 12)))
+	if nside < 1 {
+		nside = 1
+	}
+	ns := float64(nside)
+	solid := 4 * math.Pi / (12 * ns * ns)
+	var dirs []direction
+	// Rings of constant z = cos θ, indexed from 1 to 4 N_side - 1.
+	for i := 1; i < 4*nside; i++ {
+		switch {
+		case i < nside, i > 3*nside:
+			// Polar caps; 4 i' pixels per ring, with i' the ring index from the
+			// nearest pole.
+			ip := i
+			if i > 3*nside {
+				ip = 4*nside - i
+			}
+			z := 1 - float64(ip*ip)/(3*ns*ns)
+			if i > 3*nside {
+				z = -z
+			}
+			for j := 1; j <= 4*ip; j++ {
+				phi := math.Pi / (2 * float64(ip)) * (float64(j) - 0.5)
+				dirs = append(dirs, direction{theta: math.Acos(z), phi: phi, solid: solid})
+			}
+		default:
+			// Equatorial belt; 4 N_side pixels per ring, shifted by half a pixel
+			// on alternate rings.
+			z := 4.0/3 - 2*float64(i)/(3*ns)
+			shift := float64((i-nside+1)%2) / 2
+			for j := 1; j <= 4*nside; j++ {
+				phi := math.Pi / (2 * ns) * (float64(j) - shift)
+				dirs = append(dirs, direction{theta: math.Acos(z), phi: phi, solid: solid})
+			}
+		}
+	}
+	return dirs
+}
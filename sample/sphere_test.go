@@ -0,0 +1,75 @@
+package sample
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestHEALPixDirections(t *testing.T) {
+	golden := []struct {
+		n     int
+		nside int
+	}{
+		{n: 0, nside: 19}, // DefaultDirections
+		{n: 1, nside: 1},
+		{n: 12, nside: 1},
+		{n: 48, nside: 2},
+		{n: 100, nside: 3},
+		{n: 192, nside: 4},
+		{n: 3072, nside: 16},
+		{n: 49152, nside: 64},
+	}
+	for _, g := range golden {
+		dirs, err := HEALPixSphere.directions(g.n)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if want := 12 * g.nside * g.nside; len(dirs) != want {
+			t.Errorf("n=%d: number of directions mismatch; expected %d, got %d", g.n, want, len(dirs))
+		}
+		// Equal area.
+		for _, dir := range dirs {
+			if want := 4 * math.Pi / float64(len(dirs)); math.Abs(dir.solid-want) > 1e-15 {
+				t.Errorf("n=%d: solid angle mismatch; expected %g, got %g", g.n, want, dir.solid)
+				break
+			}
+		}
+	}
+}
+
+func TestSphereDirections(t *testing.T) {
+	for _, sphere := range []Sphere{UniformSphere, FibonacciSphere, HEALPixSphere} {
+		for _, n := range []int{0, 1, 12, 100, 1000, 10000} {
+			name := fmt.Sprintf("%v (n=%d)", sphere, n)
+			dirs, err := sphere.directions(n)
+			if err != nil {
+				t.Errorf("%s: unable to sample directions; %v", name, err)
+				continue
+			}
+			if len(dirs) == 0 {
+				t.Errorf("%s: no directions", name)
+				continue
+			}
+			// Solid angles sum to 4π, and directions are on the unit sphere.
+			solid, z2 := 0.0, 0.0
+			for _, dir := range dirs {
+				if !(0 <= dir.theta && dir.theta <= math.Pi && 0 <= dir.phi && dir.phi <= 2*math.Pi) {
+					t.Errorf("%s: direction (theta, phi) = (%g, %g) out of range", name, dir.theta, dir.phi)
+				}
+				solid += dir.solid
+				z := math.Cos(dir.theta)
+				z2 += z * z * dir.solid
+			}
+			if math.Abs(solid-4*math.Pi) > 1e-12*4*math.Pi {
+				t.Errorf("%s: sum of solid angles mismatch; expected 4π, got %g", name, solid)
+			}
+			// Quadrature of the integral of cos^2 θ over the sphere; 4π/3.
+			if len(dirs) >= 1000 {
+				if want := 4 * math.Pi / 3; math.Abs(z2-want) > 0.01*want {
+					t.Errorf("%s: integral of cos^2 θ mismatch; expected %g, got %g", name, want, z2)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package sample
+
+import (
+	"math"
+
+	"github.com/mewmew/orbitals/orb"
+	"github.com/mewmew/orbitals/prune"
+	"github.com/mewmew/orbitals/wave"
+	"github.com/pkg/errors"
+)
+
+// CartesianStream streams the points of the 3D-model of Cartesian, pruned by
+// the given pruning strategy, to fn in the same order as Cartesian.
+//
+// Instead of storing the points of the full grid, the grid is sampled twice;
+// the first pass computes the normalization and the pruning cutoff (see
+// prune.NewAccumulator), and the second pass normalizes, prunes and emits the
+// points. Errors returned by fn stop the stream.
+//
+// An error is returned if the sampling mode, extent or pruning strategy is
+// invalid.
+func CartesianStream(mode Mode, ext Extent, Psi wave.ComplexPsiFunc, strategy prune.Strategy, fn func(p orb.CartesianPoint) error) error {
+	acc := prune.NewAccumulator(strategy)
+	total := 0.0
+	err := visitCartesian(mode, ext, Psi, func(pt orb.CartesianPoint) error {
+		acc.Add(pt.Prob)
+		total += math.Abs(pt.Prob)
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	cutoff, err := acc.Cutoff(total)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = visitCartesian(mode, ext, Psi, func(pt orb.CartesianPoint) error {
+		if total != 0 {
+			pt.Prob /= total
+		}
+		if math.Abs(pt.Prob) < cutoff {
+			return nil
+		}
+		return fn(pt)
+	})
+	return errors.WithStack(err)
+}
+
+// SphericStream streams the points of the 3D-model of Spheric, pruned by the
+// given pruning strategy and converted to Cartesian coordinates in picometer
+// (see prune.Spheric), to fn in the same order as Spheric.
+//
+// Instead of storing the points of the full grid, the grid is sampled twice
+// (see CartesianStream). Errors returned by fn stop the stream.
+//
+// An error is returned if the sampling mode, extent or pruning strategy is
+// invalid.
+func SphericStream(mode Mode, ext Extent, Ray wave.RayFunc, strategy prune.Strategy, fn func(p orb.CartesianPoint) error) error {
+	acc := prune.NewAccumulator(strategy)
+	total := 0.0
+	err := visitSpheric(mode, ext, Ray, func(pt orb.SphericalPoint) error {
+		acc.Add(pt.Prob)
+		total += math.Abs(pt.Prob)
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	cutoff, err := acc.Cutoff(total)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = visitSpheric(mode, ext, Ray, func(pt orb.SphericalPoint) error {
+		if total != 0 {
+			pt.Prob /= total
+		}
+		if math.Abs(pt.Prob) < cutoff {
+			return nil
+		}
+		return fn(prune.ToCartesian(pt))
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,172 @@
+package sample
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mewmew/orbitals/orb"
+	"github.com/mewmew/orbitals/prune"
+	"github.com/mewmew/orbitals/wave"
+)
+
+func TestCartesianStream(t *testing.T) {
+	for _, g := range streamGolden(t) {
+		ext, err := ExtentOf(g.w)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext.Grid = 24
+		pts, err := Cartesian(g.mode, ext, g.w.Psi)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		want, err := prune.Cartesian(pts, g.strategy)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		var got []orb.CartesianPoint
+		err = CartesianStream(g.mode, ext, g.w.Psi, g.strategy, func(pt orb.CartesianPoint) error {
+			got = append(got, pt)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%s: unable to stream points; %v", g.name, err)
+			continue
+		}
+		checkPoints(t, g.name, got, want)
+	}
+}
+
+func TestSphericStream(t *testing.T) {
+	for _, g := range streamGolden(t) {
+		ext, err := ExtentOf(g.w)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		ext.Grid = 24
+		ext.Sphere = HEALPixSphere
+		ext.Directions = 192
+		pts, err := Spheric(g.mode, ext, wave.Rays(g.w.Psi))
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		want, err := prune.Spheric(pts, g.strategy)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		var got []orb.CartesianPoint
+		err = SphericStream(g.mode, ext, wave.Rays(g.w.Psi), g.strategy, func(pt orb.CartesianPoint) error {
+			got = append(got, pt)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%s: unable to stream points; %v", g.name, err)
+			continue
+		}
+		checkPoints(t, g.name, got, want)
+	}
+}
+
+func TestCartesianStreamMass(t *testing.T) {
+	w, err := wave.NewHydrogenic(wave.RealBasis, 1, 3, 2, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ext, err := ExtentOf(w)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	ext.Grid = 24
+	pts, err := Cartesian(DensityMode, ext, w.Psi)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	probs := make([]float64, len(pts))
+	for i, pt := range pts {
+		probs[i] = pt.Prob
+	}
+	for _, frac := range []float64{0.5, 0.9, 0.99} {
+		strategy := prune.Mass(frac)
+		name := fmt.Sprintf("%s (%v)", w.Label(), strategy)
+		cutoff, err := strategy.Cutoff(probs)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		// The cutoff of the Mass accumulator is rounded down by at most 1/64 of its
+		// value; thus the exact points are kept, and possibly a few more.
+		var got []orb.CartesianPoint
+		err = CartesianStream(DensityMode, ext, w.Psi, strategy, func(pt orb.CartesianPoint) error {
+			got = append(got, pt)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%s: unable to stream points; %v", name, err)
+			continue
+		}
+		j := 0
+		for _, pt := range pts {
+			keep := pt.Prob >= cutoff
+			if j < len(got) && got[j] == pt {
+				if pt.Prob < cutoff/(1+1.0/64) {
+					t.Errorf("%s: point %+v below cutoff %g kept", name, pt, cutoff)
+				}
+				j++
+			} else if keep {
+				t.Errorf("%s: point %+v above cutoff %g pruned", name, pt, cutoff)
+			}
+		}
+		if j != len(got) {
+			t.Errorf("%s: unexpected points; expected subset of Cartesian", name)
+		}
+	}
+}
+
+// streamCase is a test case of the streaming samplers.
+type streamCase struct {
+	name     string
+	mode     Mode
+	w        wave.Wavefunction
+	strategy prune.Strategy
+}
+
+// streamGolden returns the test cases of the streaming samplers; each pruning
+// strategy with an exact accumulator, in each sampling mode. The accumulators
+// of other strategies are tested by the prune package.
+func streamGolden(t *testing.T) []streamCase {
+	w, err := wave.NewHydrogenic(wave.ComplexBasis, 1, 3, 2, -1)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	strategies := []prune.Strategy{
+		prune.Abs(1e-4),
+		prune.Rel(0.01),
+		prune.TopK(500),
+	}
+	var cases []streamCase
+	for _, mode := range []Mode{DensityMode, RadialMode, SignedMode} {
+		for _, strategy := range strategies {
+			cases = append(cases, streamCase{
+				name:     fmt.Sprintf("%s (%v, %v)", w.Label(), mode, strategy),
+				mode:     mode,
+				w:        w,
+				strategy: strategy,
+			})
+		}
+	}
+	return cases
+}
+
+// checkPoints reports an error if the given points differ.
+func checkPoints(t *testing.T, name string, got, want []orb.CartesianPoint) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: number of points mismatch; expected %d, got %d", name, len(want), len(got))
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s: point %d mismatch; expected %+v, got %+v", name, i, want[i], got[i])
+			return
+		}
+	}
+}
@@ -0,0 +1,510 @@
+package wave
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// === [ Wave function algebra ] ===============================================
+
+// Combination is a linear combination sum_i c_i psi_i of wave functions psi_i
+// with the same nuclear charge (e.g. a hybrid orbital or a superposition of
+// orbitals).
+type Combination struct {
+	// Label of the linear combination (e.g. "sp3_0"); if empty, the label is
+	// derived from the terms (e.g. "0.707*2s+0.707*2p_z").
+	Name string
+	// Coefficients, c_i, of the wave functions.
+	Coeffs []complex128
+	// Wave functions, psi_i.
+	Terms []Wavefunction
+}
+
+// LinearCombination returns the linear combination sum_i c_i psi_i of the
+// given wave functions, psi_i, with coefficients, c_i.
+//
+// An error is returned if the number of coefficients and wave functions differ,
+// or if the wave functions have different nuclear charge.
+func LinearCombination(coeffs []complex128, ws []Wavefunction) (*Combination, error) {
+	if len(coeffs) != len(ws) {
+		return nil, errors.Errorf("mismatch between number of coefficients (%d) and wave functions (%d)", len(coeffs), len(ws))
+	}
+	if len(ws) == 0 {
+		return nil, errors.New("invalid linear combination; no wave functions")
+	}
+	Z, _, _, _ := ws[0].QuantumNumbers()
+	for _, w := range ws[1:] {
+		if z, _, _, _ := w.QuantumNumbers(); z != Z {
+			return nil, errors.Errorf("mismatch between nuclear charge of %q (Z=%d) and %q (Z=%d)", ws[0].Label(), Z, w.Label(), z)
+		}
+	}
+	c := &Combination{
+		Coeffs: append([]complex128(nil), coeffs...),
+		Terms:  append([]Wavefunction(nil), ws...),
+	}
+	return c, nil
+}
+
+// Add returns the sum a + b of the given wave functions.
+func Add(a, b Wavefunction) (*Combination, error) {
+	return LinearCombination([]complex128{1, 1}, []Wavefunction{a, b})
+}
+
+// Scale returns the wave function c psi, scaled by the coefficient c.
+func Scale(c complex128, w Wavefunction) *Combination {
+	return &Combination{Coeffs: []complex128{c}, Terms: []Wavefunction{w}}
+}
+
+// Normalize returns the normalized wave function psi/<psi|psi>^{1/2}, where
+// <psi|psi> is integrated numerically (see NormSquared).
+//
+// An error is returned if the wave function vanishes.
+func Normalize(w Wavefunction) (*Combination, error) {
+	norm2 := NormSquared(w)
+	if !(norm2 > 0) {
+		return nil, errors.Errorf("unable to normalize %q; vanishing wave function", w.Label())
+	}
+	c := Scale(complex(1/math.Sqrt(norm2), 0), w)
+	c.Name = w.Label()
+	return c, nil
+}
+
+// QuantumNumbers returns the nuclear charge, Z, and the quantum numbers n, l
+// and m shared by all terms of the linear combination; quantum numbers that
+// differ between terms are -1. The nuclear charge of an empty linear
+// combination is 0.
+func (c *Combination) QuantumNumbers() (Z, n, l, m int) {
+	if len(c.Terms) == 0 {
+		return 0, -1, -1, -1
+	}
+	Z, n, l, m = c.Terms[0].QuantumNumbers()
+	for _, w := range c.Terms[1:] {
+		_, wn, wl, wm := w.QuantumNumbers()
+		if wn != n {
+			n = -1
+		}
+		if wl != l {
+			l = -1
+		}
+		if wm != m {
+			m = -1
+		}
+	}
+	if l == -1 {
+		m = -1
+	}
+	return Z, n, l, m
+}
+
+// Label returns the label of the linear combination.
+func (c *Combination) Label() string {
+	if len(c.Name) > 0 {
+		return c.Name
+	}
+	var terms []string
+	for i, w := range c.Terms {
+		terms = append(terms, fmt.Sprintf("%s*%s", formatCoeff(c.Coeffs[i]), w.Label()))
+	}
+	return strings.Join(terms, "+")
+}
+
+// formatCoeff returns a compact string representation of the coefficient c.
+func formatCoeff(c complex128) string {
+	if imag(c) == 0 {
+		return fmt.Sprintf("%.3g", real(c))
+	}
+	return fmt.Sprintf("(%.3g%+.3gi)", real(c), imag(c))
+}
+
+// Psi returns psi at the spherical (rho, theta, phi)-coordinate.
+func (c *Combination) Psi(rho, theta, phi float64) complex128 {
+	var psi complex128
+	for i, w := range c.Terms {
+		psi += c.Coeffs[i] * w.Psi(rho, theta, phi)
+	}
+	return psi
+}
+
+// Radial returns the radial function R(r) of the linear combination at the
+// radius r, such that r^2 R(r)^2 is the radial probability density of psi.
+//
+// For linear combinations of hydrogen-like orbitals,
+//
+//    R(r)^2 = sum_{l,m} |sum_n c_{nlm} R_{nl}(r)|^2
+//
+// where c_{nlm} are the coefficients in the complex basis. Otherwise, |psi|^2
+// is integrated numerically over the sphere of radius r.
+func (c *Combination) Radial(r float64) float64 {
+	ts, ok := expand(c)
+	if !ok {
+		f := func(r, theta, phi float64) complex128 {
+			return complex(math.Pow(cmplx.Abs(c.Psi(r, theta, phi)), 2), 0)
+		}
+		return math.Sqrt(real(sphereIntegral(f, r, principalOrder(c))))
+	}
+	type lm struct{ l, m int }
+	sums := make(map[lm]complex128)
+	for _, t := range ts {
+		sums[lm{l: t.l, m: t.m}] += t.c * complex(radialFunc(t.Z, t.n, t.l, r), 0)
+	}
+	var sum float64
+	for _, v := range sums {
+		sum += math.Pow(cmplx.Abs(v), 2)
+	}
+	return math.Sqrt(sum)
+}
+
+// Angular returns the angular function sum_i c_i Y_i(θ, φ) of the linear
+// combination at the inclination theta and azimuth phi. The angular function
+// factors psi only if the linear combination is separable (see Separable).
+func (c *Combination) Angular(theta, phi float64) complex128 {
+	var y complex128
+	for i, w := range c.Terms {
+		y += c.Coeffs[i] * w.Angular(theta, phi)
+	}
+	return y
+}
+
+// Separable reports whether psi(r, θ, φ) = R(r) Y(θ, φ), which holds if all
+// terms are separable and share the same radial function.
+func (c *Combination) Separable() bool {
+	ts, ok := expand(c)
+	if !ok {
+		return false
+	}
+	for _, t := range ts[1:] {
+		if t.n != ts[0].n || t.l != ts[0].l {
+			return false
+		}
+	}
+	return true
+}
+
+// Energy returns the energy expectation value of the linear combination with
+// unit E_h. Terms that are not linear combinations of hydrogen-like orbitals are
+// assumed to be orthogonal.
+func (c *Combination) Energy() float64 {
+	var e, norm2 float64
+	if ts, ok := expand(c); ok {
+		for _, t := range ts {
+			p := math.Pow(cmplx.Abs(t.c), 2)
+			e += p * -Rydberg * math.Pow(float64(t.Z), 2) / math.Pow(float64(t.n), 2)
+			norm2 += p
+		}
+	} else {
+		for i, w := range c.Terms {
+			p := math.Pow(cmplx.Abs(c.Coeffs[i])*w.Norm(), 2)
+			e += p * w.Energy()
+			norm2 += p
+		}
+	}
+	if norm2 == 0 {
+		return 0
+	}
+	return e / norm2
+}
+
+// Norm returns the norm <psi|psi>^{1/2} of the linear combination; exact for
+// linear combinations of hydrogen-like orbitals, and numerically integrated
+// otherwise.
+func (c *Combination) Norm() float64 {
+	ts, ok := expand(c)
+	if !ok {
+		return math.Sqrt(NormSquared(c))
+	}
+	var sum float64
+	for _, t := range ts {
+		sum += math.Pow(cmplx.Abs(t.c), 2)
+	}
+	return math.Sqrt(sum)
+}
+
+// --- [ Expansion in hydrogen-like orbitals ] ---------------------------------
+
+// termKey identifies a hydrogen-like (n, l, m)-orbital in the complex basis
+// with nuclear charge Z.
+type termKey struct{ Z, n, l, m int }
+
+// term is a hydrogen-like (n, l, m)-orbital in the complex basis with nuclear
+// charge Z, scaled by the coefficient c.
+type term struct {
+	c          complex128
+	Z, n, l, m int
+}
+
+// expand returns the expansion of the wave function in hydrogen-like orbitals of
+// the complex basis, with terms sorted by quantum numbers. The boolean return
+// value indicates whether the wave function is a linear combination of
+// hydrogen-like orbitals.
+func expand(w Wavefunction) ([]term, bool) {
+	coeffs := make(map[termKey]complex128)
+	if !expandInto(coeffs, w, 1) {
+		return nil, false
+	}
+	var ts []term
+	for k, c := range coeffs {
+		ts = append(ts, term{c: c, Z: k.Z, n: k.n, l: k.l, m: k.m})
+	}
+	sort.Slice(ts, func(i, j int) bool {
+		a, b := ts[i], ts[j]
+		switch {
+		case a.n != b.n:
+			return a.n < b.n
+		case a.l != b.l:
+			return a.l < b.l
+		}
+		return a.m < b.m
+	})
+	return ts, len(ts) > 0
+}
+
+// expandInto adds the expansion of the wave function, scaled by c, to the
+// coefficients of hydrogen-like orbitals of the complex basis.
+func expandInto(coeffs map[termKey]complex128, w Wavefunction, c complex128) bool {
+	switch w := w.(type) {
+	case *Hydrogenic:
+		if w.Basis == ComplexBasis || w.M == 0 {
+			coeffs[termKey{w.Z, w.N, w.L, w.M}] += c
+			return true
+		}
+		cm, cneg := realHarmonicCoeffs(w.M)
+		coeffs[termKey{w.Z, w.N, w.L, w.M}] += c * cm
+		coeffs[termKey{w.Z, w.N, w.L, -w.M}] += c * cneg
+		return true
+	case *Combination:
+		for i, t := range w.Terms {
+			if !expandInto(coeffs, t, c*w.Coeffs[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// realHarmonicCoeffs returns the coefficients of the real spherical harmonic
+// S_l^m in terms of the complex spherical harmonics Y_l^m and Y_l^{-m}, for m
+// != 0, without the Condon-Shortley phase.
+//
+//    S_l^m = (Y_l^m + Y_l^{-m})/√2     if m > 0
+//    S_l^m = i (Y_l^m - Y_l^{-m})/√2   if m < 0
+func realHarmonicCoeffs(m int) (cm, cneg complex128) {
+	if m > 0 {
+		return 1 / math.Sqrt2, 1 / math.Sqrt2
+	}
+	return 1i / math.Sqrt2, -1i / math.Sqrt2
+}
+
+// --- [ Numerical integration ] -----------------------------------------------
+
+// NormSquared returns <psi|psi>, the integral of |psi|^2 over all space, as
+// computed by numerical integration (see integrate).
+func NormSquared(w Wavefunction) float64 {
+	f := func(r, theta, phi float64) complex128 {
+		return complex(math.Pow(cmplx.Abs(w.Psi(r, theta, phi)), 2), 0)
+	}
+	return real(integrate(f, minCharge(w), principalOrder(w)))
+}
+
+// EnclosingRadius returns the radius of the sphere, centered at the nucleus,
+// which encloses the given fraction (e.g. 0.999 for 99.9%) of the probability
+// |psi|^2 of the wave function, as computed by numerical integration of the
+// radial probability density r^2 R(r)^2 (see Wavefunction.Radial). The radial
+// functions of hydrogen-like orbitals and their linear combinations are exact,
+// and only for other wave functions is |psi|^2 integrated over each sphere.
+//
+// The radius is of order n^2/Z for the principal quantum number n and nuclear
+// charge Z; e.g. 5.6 a_0 (297 pm) for the 1s-orbital of hydrogen at 99.9%.
+//
+// An error is returned if the fraction is not in (0, 1).
+func EnclosingRadius(w Wavefunction, frac float64) (float64, error) {
+	if !(0 < frac && frac < 1) {
+		return 0, errors.Errorf("invalid enclosed fraction; expected 0 < frac < 1, got %g", frac)
+	}
+	// Cumulative radial probability, by the trapezoidal rule.
+	rmax, nr := radialRange(minCharge(w), principalOrder(w))
+	h := rmax / float64(nr)
+	cum := make([]float64, nr+1)
+	prev := 0.0 // radial probability density at r = 0.
+	for i := 1; i <= nr; i++ {
+		r := float64(i) * h
+		R := w.Radial(r)
+		cur := r * r * R * R
+		cum[i] = cum[i-1] + (prev+cur)*h/2
+		prev = cur
+	}
+	target := frac * cum[nr]
+	for i := 1; i <= nr; i++ {
+		if cum[i] >= target {
+			// Interpolate linearly within the step.
+			t := (target - cum[i-1]) / (cum[i] - cum[i-1])
+			return (float64(i-1) + t) * h, nil
+		}
+	}
+	return rmax, nil
+}
+
+// Overlap returns the overlap integral <a|b> of the given wave functions; exact
+// for linear combinations of hydrogen-like orbitals with the same nuclear
+// charge, and numerically integrated otherwise (see integrate). Orbitals with
+// different nuclear charge are not orthogonal; e.g. <1s (Z=1)|1s (Z=2)> =
+// 16√2/27 ≈ 0.838.
+func Overlap(a, b Wavefunction) complex128 {
+	if ta, ok := expand(a); ok {
+		if tb, ok := expand(b); ok && sameCharge(append(ta, tb...)) {
+			coeffs := make(map[termKey]complex128)
+			for _, t := range ta {
+				coeffs[termKey{Z: t.Z, n: t.n, l: t.l, m: t.m}] = t.c
+			}
+			var sum complex128
+			for _, t := range tb {
+				sum += cmplx.Conj(coeffs[termKey{Z: t.Z, n: t.n, l: t.l, m: t.m}]) * t.c
+			}
+			return sum
+		}
+	}
+	Z := minCharge(a)
+	if Zb := minCharge(b); Zb < Z {
+		Z = Zb
+	}
+	n := principalOrder(a)
+	if nb := principalOrder(b); nb > n {
+		n = nb
+	}
+	f := func(r, theta, phi float64) complex128 {
+		return cmplx.Conj(a.Psi(r, theta, phi)) * b.Psi(r, theta, phi)
+	}
+	return integrate(f, Z, n)
+}
+
+// sameCharge reports whether the given terms share the same nuclear charge.
+func sameCharge(ts []term) bool {
+	for _, t := range ts {
+		if t.Z != ts[0].Z {
+			return false
+		}
+	}
+	return true
+}
+
+// minCharge returns the smallest nuclear charge of the terms of the wave
+// function; which determines the radial extent of numerical integration (see
+// radialRange). The nuclear charge of empty linear combinations is taken to be
+// 1.
+func minCharge(w Wavefunction) int {
+	if c, ok := w.(*Combination); ok && len(c.Terms) > 0 {
+		Z := minCharge(c.Terms[0])
+		for _, t := range c.Terms[1:] {
+			if tZ := minCharge(t); tZ < Z {
+				Z = tZ
+			}
+		}
+		return Z
+	}
+	Z, _, _, _ := w.QuantumNumbers()
+	if Z < 1 {
+		return 1
+	}
+	return Z
+}
+
+// integrate returns the integral of f over all space, for wave functions with
+// nuclear charge Z and principal quantum numbers up to n; using Simpson's rule
+// in r, Gauss-Legendre quadrature in cos θ and the trapezoidal rule in φ.
+//
+// The radial extent and angular resolution are chosen such that the angular
+// integral is exact for products of orbitals with l < n.
+func integrate(f func(r, theta, phi float64) complex128, Z, n int) complex128 {
+	rmax, nr := radialRange(Z, n)
+	h := rmax / float64(nr)
+	var sum complex128
+	for i := 0; i <= nr; i++ {
+		r := float64(i) * h
+		weight := 2.0
+		switch {
+		case i == 0 || i == nr:
+			weight = 1
+		case i%2 == 1:
+			weight = 4
+		}
+		sum += complex(weight*r*r, 0) * sphereIntegral(f, r, n)
+	}
+	return sum * complex(h/3, 0)
+}
+
+// radialRange returns the radial extent and (even) number of radial intervals
+// of numerical integration, for wave functions with nuclear charge Z and
+// principal quantum numbers up to n. The radial extent reaches beyond the outer
+// classical turning point 2n^2 a_0/Z.
+func radialRange(Z, n int) (rmax float64, nr int) {
+	rmax = float64(2*n*n+20*n) * BohrRadius / float64(Z)
+	nr = 400 * n
+	return rmax, nr
+}
+
+// sphereIntegral returns the integral of f over the unit sphere at the radius
+// r, exact for products of orbitals with azimuthal quantum number l < order.
+func sphereIntegral(f func(r, theta, phi float64) complex128, r float64, order int) complex128 {
+	xs, ws := gaussLegendre(order + 1)
+	nphi := 2*order + 1
+	dphi := 2 * math.Pi / float64(nphi)
+	var sum complex128
+	for i, x := range xs {
+		theta := math.Acos(x)
+		for j := 0; j < nphi; j++ {
+			phi := float64(j) * dphi
+			sum += complex(ws[i]*dphi, 0) * f(r, theta, phi)
+		}
+	}
+	return sum
+}
+
+// principalOrder returns the largest principal quantum number of the wave
+// function, or 1 if unknown.
+func principalOrder(w Wavefunction) int {
+	if c, ok := w.(*Combination); ok {
+		n := 1
+		for _, t := range c.Terms {
+			if tn := principalOrder(t); tn > n {
+				n = tn
+			}
+		}
+		return n
+	}
+	if _, n, _, _ := w.QuantumNumbers(); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// gaussLegendre returns the nodes and weights of the n-point Gauss-Legendre
+// quadrature on [-1, 1].
+//
+// ref: https://en.wikipedia.org/wiki/Gauss%E2%80%93Legendre_quadrature
+func gaussLegendre(n int) (xs, ws []float64) {
+	xs = make([]float64, n)
+	ws = make([]float64, n)
+	for i := 0; i < n; i++ {
+		// Initial guess of the i:th root of P_n(x).
+		x := math.Cos(math.Pi * (float64(i) + 0.75) / (float64(n) + 0.5))
+		var dp float64
+		for iter := 0; iter < 100; iter++ {
+			p := assocLegendre(n, 0, x)
+			// P'_n(x) = n (x P_n(x) - P_{n-1}(x))/(x^2 - 1)
+			dp = float64(n) * (x*p - assocLegendre(n-1, 0, x)) / (x*x - 1)
+			dx := p / dp
+			x -= dx
+			if math.Abs(dx) < 1e-15 {
+				break
+			}
+		}
+		xs[i] = x
+		ws[i] = 2 / ((1 - x*x) * dp * dp)
+	}
+	return xs, ws
+}
@@ -0,0 +1,129 @@
+package wave
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestOverlap(t *testing.T) {
+	// hydrogenic returns the specified hydrogen-like orbital in the real basis.
+	hydrogenic := func(Z, n, l, m int) *Hydrogenic {
+		w, err := NewHydrogenic(RealBasis, Z, n, l, m)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return w
+	}
+	// 1s overlap of nuclear charges Z1 and Z2; 8 (Z1 Z2)^{3/2} / (Z1+Z2)^3.
+	overlap1s := func(Z1, Z2 float64) float64 {
+		return 8 * math.Pow(Z1*Z2, 1.5) / math.Pow(Z1+Z2, 3)
+	}
+	golden := []struct {
+		a, b Wavefunction
+		want complex128
+		// Tolerance; the overlap of orbitals with different nuclear charge is
+		// numerically integrated.
+		tolerance float64
+	}{
+		{a: hydrogenic(1, 1, 0, 0), b: hydrogenic(1, 1, 0, 0), want: 1, tolerance: 1e-12},
+		{a: hydrogenic(1, 1, 0, 0), b: hydrogenic(1, 2, 0, 0), want: 0, tolerance: 1e-12},
+		{a: hydrogenic(3, 3, 2, 1), b: hydrogenic(3, 3, 2, 1), want: 1, tolerance: 1e-12},
+		{a: hydrogenic(1, 1, 0, 0), b: hydrogenic(2, 1, 0, 0), want: complex(overlap1s(1, 2), 0), tolerance: 1e-4},
+		{a: hydrogenic(2, 1, 0, 0), b: hydrogenic(1, 1, 0, 0), want: complex(overlap1s(2, 1), 0), tolerance: 1e-4},
+		{a: hydrogenic(1, 1, 0, 0), b: hydrogenic(3, 1, 0, 0), want: complex(overlap1s(1, 3), 0), tolerance: 1e-4},
+		// Orthogonal by symmetry, regardless of nuclear charge.
+		{a: hydrogenic(1, 1, 0, 0), b: hydrogenic(2, 2, 1, 0), want: 0, tolerance: 1e-9},
+	}
+	for _, g := range golden {
+		a, b := g.a, g.b
+		Za, _, _, _ := a.QuantumNumbers()
+		Zb, _, _, _ := b.QuantumNumbers()
+		name := fmt.Sprintf("<%s (Z=%d)|%s (Z=%d)>", a.Label(), Za, b.Label(), Zb)
+		if got := Overlap(a, b); !(cmplx.Abs(got-g.want) <= g.tolerance) {
+			t.Errorf("%s: overlap mismatch; expected %v, got %v", name, g.want, got)
+		}
+	}
+}
+
+// nanWavefunction is a wave function evaluating to NaN.
+type nanWavefunction struct {
+	*Hydrogenic
+}
+
+// Psi returns NaN.
+func (nanWavefunction) Psi(rho, theta, phi float64) complex128 {
+	return cmplx.NaN()
+}
+
+func TestCheckOrthonormal(t *testing.T) {
+	s, err := NewHydrogenic(RealBasis, 1, 1, 0, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	p, err := NewHydrogenic(RealBasis, 1, 2, 1, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	sZ2, err := NewHydrogenic(RealBasis, 2, 1, 0, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	golden := []struct {
+		name string
+		ws   []Wavefunction
+		ok   bool
+	}{
+		{name: "1s, 2p_z", ws: []Wavefunction{s, p}, ok: true},
+		{name: "1s (Z=1), 1s (Z=2)", ws: []Wavefunction{s, sZ2}, ok: false},
+		{name: "NaN", ws: []Wavefunction{nanWavefunction{s}}, ok: false},
+	}
+	for _, g := range golden {
+		err := CheckOrthonormal(g.ws)
+		if ok := err == nil; ok != g.ok {
+			t.Errorf("%s: orthonormality mismatch; expected %v, got %v (%v)", g.name, g.ok, ok, err)
+		}
+	}
+}
+
+func TestEnclosingRadiusInvalid(t *testing.T) {
+	w, err := NewHydrogenic(RealBasis, 1, 1, 0, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, frac := range []float64{0, 1, -0.5, 1.5, math.NaN()} {
+		if _, err := EnclosingRadius(w, frac); err == nil {
+			t.Errorf("frac=%g: expected error for invalid fraction, got nil", frac)
+		}
+	}
+}
+
+func TestEnclosingRadius(t *testing.T) {
+	// Probability enclosed by the sphere of radius r of the 1s-orbital with
+	// nuclear charge Z.
+	//
+	//    P(r) = 1 - e^{-2x} (1 + 2x + 2x^2), where x = Zr/a_0
+	enclosed1s := func(Z int, r float64) float64 {
+		x := float64(Z) * r / BohrRadius
+		return 1 - math.Exp(-2*x)*(1+2*x+2*x*x)
+	}
+	for _, Z := range []int{1, 2, 5} {
+		w, err := NewHydrogenic(RealBasis, Z, 1, 0, 0)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		for _, frac := range []float64{0.5, 0.9, 0.999} {
+			r, err := EnclosingRadius(w, frac)
+			if err != nil {
+				t.Errorf("%s (Z=%d, %g): unable to compute enclosing radius; %v", w.Label(), Z, frac, err)
+				continue
+			}
+			// The radial probability is integrated by the trapezoidal rule, in steps
+			// of 0.055 a_0/Z for n=1 (see radialRange).
+			if got := enclosed1s(Z, r); math.Abs(got-frac) > 1e-3 {
+				t.Errorf("%s (Z=%d, %g): enclosed probability mismatch; expected %g, got %g (r = %g a_0)", w.Label(), Z, frac, frac, got, r)
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package wave
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/pkg/errors"
+)
+
+// === [ Bond-directed hybrid orbitals ] =======================================
+
+// BondHybrids returns the sp hybrid orbitals of the n-shell with nuclear charge
+// Z, pointing along the given bond vectors (one to four), followed by lone pair
+// hybrid orbitals completing the set of four orbitals.
+//
+// The s- and p-character of each bond hybrid is computed from the bond angles,
+// based on the orthogonality condition of Coulson
+//
+//    1 + λ_i λ_j cos θ_ij = 0
+//
+// where the bond hybrid along the unit vector u_i is (s + λ_i p_{u_i})/(1 +
+// λ_i^2)^{1/2}, and θ_ij is the angle between bonds i and j; thus bonds with
+// smaller angles have more p-character (Bent's rule). The s-character of the
+// i:th bond hybrid is 1/(1 + λ_i^2), and its hybridization is sp^{λ_i^2}. The
+// s- and p-character not used by bond hybrids is distributed equally over the
+// lone pair hybrids.
+//
+// Bond hybrids are labelled by their hybridization (e.g. "bond_0_sp4.00"), as
+// are lone pair hybrids (e.g. "lone_0_sp2.31").
+//
+// An error is returned if the bond angles are 90° or less, or if the bond
+// angles are inconsistent with orthogonal hybrid orbitals.
+func BondHybrids(Z, n int, bonds [][3]float64) ([]*Combination, error) {
+	if !(n >= 2) {
+		return nil, errors.WithStack(&InvalidQuantumNumberError{Name: "n", Value: n, Range: "n >= 2"})
+	}
+	if err := CheckQuantumNumbers(Z, n, 1, 0); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(bonds) < 1 || len(bonds) > 4 {
+		return nil, errors.Errorf("invalid number of bonds; expected 1 to 4, got %d", len(bonds))
+	}
+	// Unit bond vectors.
+	us := make([][3]float64, len(bonds))
+	for i, b := range bonds {
+		norm := math.Sqrt(b[0]*b[0] + b[1]*b[1] + b[2]*b[2])
+		if norm == 0 {
+			return nil, errors.Errorf("invalid bond vector %d; zero length", i)
+		}
+		us[i] = [3]float64{b[0] / norm, b[1] / norm, b[2] / norm}
+	}
+	lambdas, err := coulsonLambdas(us)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Coefficients of the bond hybrids, in the basis of the s-, p_x-, p_y- and
+	// p_z-orbitals.
+	var coeffs [][]float64
+	for i, u := range us {
+		l := lambdas[i]
+		norm := math.Sqrt(1 + l*l)
+		coeffs = append(coeffs, []float64{1 / norm, l * u[0] / norm, l * u[1] / norm, l * u[2] / norm})
+	}
+	coeffs = append(coeffs, lonePairs(coeffs)...)
+	orbitals := [][3]int{{n, 0, 0}, {n, 1, +1}, {n, 1, -1}, {n, 1, 0}}
+	var ws []Wavefunction
+	for _, o := range realOrbitals(Z, orbitals) {
+		ws = append(ws, o)
+	}
+	hs, err := NewHybrids("bond", ws, coeffs)
+	if err != nil {
+		return nil, errors.Wrap(err, "inconsistent bond angles")
+	}
+	for i, h := range hs {
+		kind, j := "bond", i
+		if i >= len(bonds) {
+			kind, j = "lone", i-len(bonds)
+		}
+		h.Name = fmt.Sprintf("%s_%d_%s", kind, j, hybridization(coeffs[i][0]*coeffs[i][0]))
+	}
+	return hs, nil
+}
+
+// coulsonLambdas returns the hybridization parameters λ_i of bond hybrids
+// pointing along the given unit vectors, such that 1 + λ_i λ_j cos θ_ij = 0.
+func coulsonLambdas(us [][3]float64) ([]float64, error) {
+	// cosines of bond angles.
+	cos := func(i, j int) float64 {
+		return us[i][0]*us[j][0] + us[i][1]*us[j][1] + us[i][2]*us[j][2]
+	}
+	for i := range us {
+		for j := i + 1; j < len(us); j++ {
+			if c := cos(i, j); !(c < -1e-12) {
+				return nil, errors.Errorf("invalid angle between bonds %d and %d; expected > 90°, got %.1f°", i, j, math.Acos(math.Max(-1, math.Min(1, c)))*180/math.Pi)
+			}
+		}
+	}
+	switch len(us) {
+	case 1:
+		// Unconstrained; use sp^3 hybridization.
+		return []float64{math.Sqrt(3)}, nil
+	case 2:
+		// Equivalent bond hybrids; λ^2 = -1/cos θ.
+		l := math.Sqrt(-1 / cos(0, 1))
+		return []float64{l, l}, nil
+	}
+	// λ_i^2 = -cos θ_jk/(cos θ_ij cos θ_ik), for distinct i, j and k.
+	lambdas := make([]float64, len(us))
+	for i := 0; i < 3; i++ {
+		j, k := (i+1)%3, (i+2)%3
+		lambdas[i] = math.Sqrt(-cos(j, k) / (cos(i, j) * cos(i, k)))
+	}
+	// The fourth bond hybrid is determined by its orthogonality to the first;
+	// its orthogonality to the others is verified by NewHybrids.
+	if len(us) == 4 {
+		lambdas[3] = -1 / (lambdas[0] * cos(0, 3))
+	}
+	return lambdas, nil
+}
+
+// lonePairs returns the coefficients of lone pair hybrids completing the given
+// orthonormal bond hybrids to an orthonormal basis of the s-, p_x-, p_y- and
+// p_z-orbitals. The remaining s-character is distributed equally over the lone
+// pair hybrids.
+func lonePairs(bonds [][]float64) [][]float64 {
+	dot := func(a, b []float64) float64 {
+		var sum float64
+		for i := range a {
+			sum += a[i] * b[i]
+		}
+		return sum
+	}
+	// orthogonalize returns the normalized component of v orthogonal to vs, or
+	// nil if v is within the span of vs.
+	orthogonalize := func(v []float64, vs [][]float64) []float64 {
+		w := append([]float64(nil), v...)
+		for _, u := range vs {
+			d := dot(w, u)
+			for i := range w {
+				w[i] -= d * u[i]
+			}
+		}
+		norm := math.Sqrt(dot(w, w))
+		if norm < 1e-9 {
+			return nil
+		}
+		for i := range w {
+			w[i] /= norm
+		}
+		return w
+	}
+	k := 4 - len(bonds)
+	if k == 0 {
+		return nil
+	}
+	// The first basis vector of the lone pair space holds all its s-character,
+	// and the remaining basis vectors are pure p.
+	span := append([][]float64(nil), bonds...)
+	var basis [][]float64
+	for i := 0; i < 4 && len(basis) < k; i++ {
+		e := make([]float64, 4)
+		e[i] = 1
+		if w := orthogonalize(e, span); w != nil {
+			span = append(span, w)
+			basis = append(basis, w)
+		}
+	}
+	// Distribute the s-character equally using the Helmert matrix H, an
+	// orthogonal matrix whose first row is constant 1/√k.
+	var lps [][]float64
+	for i := 0; i < k; i++ {
+		lp := make([]float64, 4)
+		for j, b := range basis {
+			h := 1 / math.Sqrt(float64(k))
+			if j > 0 {
+				switch {
+				case i < j:
+					h = 1 / math.Sqrt(float64(j*(j+1)))
+				case i == j:
+					h = -float64(j) / math.Sqrt(float64(j*(j+1)))
+				default:
+					h = 0
+				}
+			}
+			for c := range lp {
+				lp[c] += h * b[c]
+			}
+		}
+		lps = append(lps, lp)
+	}
+	return lps
+}
+
+// SCharacter returns the s-character |<ns|psi>|^2 of the wave function, where
+// ns is the s-orbital of the n-shell.
+func SCharacter(w Wavefunction, n int) float64 {
+	Z, _, _, _ := w.QuantumNumbers()
+	s := &Hydrogenic{Z: Z, N: n, L: 0, M: 0, Basis: RealBasis}
+	return math.Pow(cmplx.Abs(Overlap(s, w)), 2)
+}
+
+// hybridization returns the hybridization (e.g. "sp2.31") of a hybrid orbital
+// with the given s-character.
+func hybridization(s float64) string {
+	switch {
+	case s < 1e-9:
+		return "p"
+	case s > 1-1e-9:
+		return "s"
+	}
+	return fmt.Sprintf("sp%.2f", (1-s)/s)
+}
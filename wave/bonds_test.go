@@ -0,0 +1,75 @@
+package wave
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBondHybrids(t *testing.T) {
+	// Water; bond angle of 104.5° in the xy-plane.
+	half := 104.5 / 2 * math.Pi / 180
+	bonds := [][3]float64{
+		{math.Sin(half), math.Cos(half), 0},
+		{-math.Sin(half), math.Cos(half), 0},
+	}
+	hs, err := BondHybrids(1, 2, bonds)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var ws []Wavefunction
+	for _, h := range hs {
+		ws = append(ws, h)
+	}
+	if err := CheckOrthonormal(ws); err != nil {
+		t.Errorf("%v", err)
+	}
+	// Bond hybrids are sp^3.99 (λ^2 = -1/cos 104.5°), with about 20% s-character,
+	// and the remaining s-character is shared by the two lone pairs.
+	golden := []struct {
+		name string
+		s    float64
+	}{
+		{name: "bond_0_sp3.99", s: 0.2003},
+		{name: "bond_1_sp3.99", s: 0.2003},
+		{name: "lone_0_sp2.34", s: 0.2997},
+		{name: "lone_1_sp2.34", s: 0.2997},
+	}
+	if len(hs) != len(golden) {
+		t.Fatalf("number of hybrid orbitals mismatch; expected %d, got %d", len(golden), len(hs))
+	}
+	for i, g := range golden {
+		h := hs[i]
+		if h.Label() != g.name {
+			t.Errorf("label mismatch; expected %q, got %q", g.name, h.Label())
+		}
+		if got := SCharacter(h, 2); math.Abs(got-g.s) > 1e-4 {
+			t.Errorf("%s: s-character mismatch; expected %.4f, got %.4f", g.name, g.s, got)
+		}
+	}
+}
+
+func TestBondHybridsInvalidAngle(t *testing.T) {
+	golden := []struct {
+		bonds [][3]float64
+	}{
+		// 90°.
+		{bonds: [][3]float64{{1, 0, 0}, {0, 1, 0}}},
+		// 60°.
+		{bonds: [][3]float64{{1, 0, 0}, {0.5, math.Sqrt(3) / 2, 0}}},
+		// 0°.
+		{bonds: [][3]float64{{0, 0, 1}, {0, 0, 2}}},
+		// Three bonds; 90° between the first and the last.
+		{bonds: [][3]float64{{1, 0, 0}, {-1, 1, 0}, {0, -1, 0}}},
+	}
+	for _, g := range golden {
+		_, err := BondHybrids(1, 2, g.bonds)
+		if err == nil {
+			t.Errorf("%v: expected error for bond angle of 90° or less, got nil", g.bonds)
+			continue
+		}
+		if !strings.Contains(err.Error(), "invalid angle between bonds") {
+			t.Errorf("%v: error mismatch; expected invalid bond angle, got %v", g.bonds, err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package wave
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/pkg/errors"
+)
+
+// === [ Hybrid orbital builder ] ==============================================
+
+// orthonormalTolerance is the tolerance of overlap integrals when verifying
+// that a set of wave functions is orthonormal.
+const orthonormalTolerance = 1e-9
+
+// NewHybrids returns the hybrid orbitals of the specified hybridization (e.g.
+// "sp3d"), as linear combinations of the given orthonormal orbitals. The i:th
+// row of the coefficient matrix holds the coefficients of the i:th hybrid
+// orbital, one column per orbital.
+//
+// An error is returned if the dimensions of the coefficient matrix do not match
+// the number of orbitals, or if the hybrid orbitals are not orthonormal.
+func NewHybrids(hybrid string, orbitals []Wavefunction, coeffs [][]float64) ([]*Combination, error) {
+	var hs []*Combination
+	for i, row := range coeffs {
+		if len(row) != len(orbitals) {
+			return nil, errors.Errorf("mismatch between number of coefficients (%d) of %s hybrid orbital %d and orbitals (%d)", len(row), hybrid, i, len(orbitals))
+		}
+		cs := make([]complex128, len(row))
+		for j, c := range row {
+			cs[j] = complex(c, 0)
+		}
+		h, err := LinearCombination(cs, orbitals)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		h.Name = fmt.Sprintf("%s_%d", hybrid, i)
+		hs = append(hs, h)
+	}
+	var ws []Wavefunction
+	for _, h := range hs {
+		ws = append(ws, h)
+	}
+	if err := CheckOrthonormal(ws); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return hs, nil
+}
+
+// DirectedHybrids returns the hybrid orbitals of the specified hybridization
+// (e.g. "sp3d"), as linear combinations of the given real orbitals, with one
+// hybrid orbital pointing along each of the target directions.
+//
+// The coefficients of the i:th hybrid orbital are initially the values of the
+// real spherical harmonics of the orbitals in the i:th direction, which
+// maximizes the amplitude of the hybrid orbital in that direction. The hybrid
+// orbitals are then symmetrically orthonormalized (Löwdin orthogonalization),
+// which yields the orthonormal set closest to the initial one.
+//
+// An error is returned if the number of directions and orbitals differ, or if
+// the directions do not span independent hybrid orbitals.
+func DirectedHybrids(hybrid string, orbitals []*Hydrogenic, dirs [][3]float64) ([]*Combination, error) {
+	if len(dirs) != len(orbitals) {
+		return nil, errors.Errorf("mismatch between number of directions (%d) and orbitals (%d) of %s hybrid orbitals", len(dirs), len(orbitals), hybrid)
+	}
+	a := make([][]float64, len(dirs))
+	for i, dir := range dirs {
+		_, theta, phi := SphericalFromCartesian(dir[0], dir[1], dir[2])
+		a[i] = make([]float64, len(orbitals))
+		for j, o := range orbitals {
+			a[i][j] = real(o.Angular(theta, phi))
+		}
+	}
+	coeffs, err := lowdin(a)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to orthonormalize %s hybrid orbitals", hybrid)
+	}
+	var ws []Wavefunction
+	for _, o := range orbitals {
+		ws = append(ws, o)
+	}
+	return NewHybrids(hybrid, ws, coeffs)
+}
+
+// CheckOrthonormal verifies that the given wave functions are orthonormal;
+// i.e. that <psi_i|psi_j> = δ_ij.
+func CheckOrthonormal(ws []Wavefunction) error {
+	for i, a := range ws {
+		for j, b := range ws[i:] {
+			want := 0.0
+			if j == 0 {
+				want = 1
+			}
+			// Reject NaN overlaps.
+			if got := Overlap(a, b); !(cmplx.Abs(got-complex(want, 0)) <= orthonormalTolerance) {
+				return errors.Errorf("non-orthonormal wave functions; expected <%s|%s> = %g, got %s", a.Label(), b.Label(), want, formatCoeff(got))
+			}
+		}
+	}
+	return nil
+}
+
+// lowdin returns the symmetrically orthonormalized rows of the square matrix a;
+// i.e. (a a^T)^{-1/2} a.
+//
+// ref: https://en.wikipedia.org/wiki/Orthogonalization#Local_orthogonalization
+func lowdin(a [][]float64) ([][]float64, error) {
+	n := len(a)
+	// Overlap matrix s = a a^T.
+	s := make([][]float64, n)
+	for i := range a {
+		s[i] = make([]float64, n)
+		for j := range a {
+			for k := range a[i] {
+				s[i][j] += a[i][k] * a[j][k]
+			}
+		}
+	}
+	// s^{-1/2} = V D^{-1/2} V^T, where s = V D V^T.
+	vals, vecs := jacobiEigen(s)
+	for _, v := range vals {
+		if v < orthonormalTolerance {
+			return nil, errors.Errorf("linearly dependent rows; eigenvalue %.3g of overlap matrix", v)
+		}
+	}
+	c := make([][]float64, n)
+	for i := range a {
+		c[i] = make([]float64, len(a[i]))
+		for j := range a {
+			// (s^{-1/2})_{ij}
+			var sij float64
+			for k, v := range vals {
+				sij += vecs[i][k] * vecs[j][k] / math.Sqrt(v)
+			}
+			for k := range a[j] {
+				c[i][k] += sij * a[j][k]
+			}
+		}
+	}
+	return c, nil
+}
+
+// jacobiEigen returns the eigenvalues and eigenvectors of the symmetric matrix
+// a, using the cyclic Jacobi eigenvalue algorithm. The k:th column of vecs is
+// the eigenvector of the k:th eigenvalue.
+//
+// ref: https://en.wikipedia.org/wiki/Jacobi_eigenvalue_algorithm
+func jacobiEigen(a [][]float64) (vals []float64, vecs [][]float64) {
+	n := len(a)
+	m := make([][]float64, n)
+	vecs = make([][]float64, n)
+	for i := range a {
+		m[i] = append([]float64(nil), a[i]...)
+		vecs[i] = make([]float64, n)
+		vecs[i][i] = 1
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		var off float64
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += m[i][j] * m[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if m[p][q] == 0 {
+					continue
+				}
+				// Rotation angle which annihilates m[p][q].
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p] = c*mkp - s*mkq
+					m[k][q] = s*mkp + c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k] = c*mpk - s*mqk
+					m[q][k] = s*mpk + c*mqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := vecs[k][p], vecs[k][q]
+					vecs[k][p] = c*vkp - s*vkq
+					vecs[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	vals = make([]float64, n)
+	for i := range vals {
+		vals[i] = m[i][i]
+	}
+	return vals, vecs
+}
@@ -0,0 +1,139 @@
+package wave
+
+import (
+	"math"
+)
+
+// Closed forms of the 1s-, 2s-, 3s-, 2p-, 3p- and 3d-orbitals, used as
+// references for psiOrbital. The m=±1 and m=±2 orbitals are the real parts of
+// the complex orbitals, with cos(mφ) dependence. The closed forms of the real
+// 2p_x- and 2p_y-orbitals are used as references for psiRealOrbital.
+
+// === [ s-orbitals ] ==========================================================
+
+// psi1SOrbital returns the psi function of the 1s-orbital (n=1, l=0, m=0) with
+// nuclear charge Z.
+//
+// ref: https://chemistrygod.com/atomic-orbital#s-orbital
+func psi1SOrbital(Z int) PsiFunc {
+	// a is the reduced Bohr radius a_0/Z, and rho is the radius.
+	a := BohrRadius / float64(Z)
+	return func(rho, theta, phi float64) float64 {
+		return (1.0 / math.SqrtPi) * math.Pow(1.0/a, 3.0/2.0) * math.Exp(-rho/a)
+	}
+}
+
+// psi2SOrbital returns the psi function of the 2s-orbital (n=2, l=0, m=0) with
+// nuclear charge Z.
+//
+// ref: https://chemistrygod.com/atomic-orbital#s-orbital
+func psi2SOrbital(Z int) PsiFunc {
+	// a is the reduced Bohr radius a_0/Z, and rho is the radius.
+	a := BohrRadius / float64(Z)
+	return func(rho, theta, phi float64) float64 {
+		return (1.0 / (math.Sqrt(32) * math.SqrtPi)) * math.Pow(1.0/a, 3.0/2.0) * (2.0 - rho/a) * math.Exp(-rho/(2*a))
+	}
+}
+
+// psi3SOrbital returns the psi function of the 3s-orbital (n=3, l=0, m=0) with
+// nuclear charge Z.
+//
+// ref: https://chemistrygod.com/atomic-orbital#s-orbital
+func psi3SOrbital(Z int) PsiFunc {
+	// a is the reduced Bohr radius a_0/Z, and rho is the radius.
+	a := BohrRadius / float64(Z)
+	return func(rho, theta, phi float64) float64 {
+		return (1.0 / (81 * math.Sqrt(3) * math.SqrtPi)) * math.Pow(1.0/a, 3.0/2.0) * (27.0 - (18.0*rho)/a + (2*rho*rho)/(a*a)) * math.Exp(-rho/(3*a))
+	}
+}
+
+// === [ p-orbitals ] ==========================================================
+
+// psi2POrbital returns the psi function of the 2p-orbitals (n=2, l=1,
+// m={-1,0,1}) with nuclear charge Z.
+//
+// ref: https://chemistrygod.com/atomic-orbital#p-orbital
+func psi2POrbital(Z, m int) PsiFunc {
+	// a is the reduced Bohr radius a_0/Z, and rho is the radius.
+	a := BohrRadius / float64(Z)
+	if m == 0 {
+		// 2p-orbital (n=2, l=1, m=0)
+		return func(rho, theta, phi float64) float64 {
+			return (1.0 / (math.Sqrt(32) * math.SqrtPi)) * math.Pow(1.0/a, 3.0/2.0) * (rho / a) * math.Exp(-rho/(2*a)) * math.Cos(theta)
+		}
+	}
+	// 2p-orbitals (n=2, l=1, m=+-1)
+	return func(rho, theta, phi float64) float64 {
+		return (1.0 / (math.Sqrt(64) * math.SqrtPi)) * math.Pow(1.0/a, 3.0/2.0) * (rho / a) * math.Exp(-rho/(2*a)) * math.Sin(theta) * math.Cos(float64(m)*phi)
+	}
+}
+
+// psi3POrbital returns the psi function of the 3p-orbitals (n=3, l=1,
+// m={-1,0,1}) with nuclear charge Z.
+//
+// ref: https://chemistrygod.com/atomic-orbital#p-orbital
+func psi3POrbital(Z, m int) PsiFunc {
+	// a is the reduced Bohr radius a_0/Z, and rho is the radius.
+	a := BohrRadius / float64(Z)
+	if m == 0 {
+		// 3p-orbital (n=3, l=1, m=0)
+		return func(rho, theta, phi float64) float64 {
+			return (1.0 / 81.0) * (math.Sqrt(2) / math.SqrtPi) * math.Pow(1.0/a, 3.0/2.0) * (6*rho/a - (rho*rho)/(a*a)) * math.Exp(-rho/(3*a)) * math.Cos(theta)
+		}
+	}
+	// 3p-orbitals (n=3, l=1, m=+-1)
+	return func(rho, theta, phi float64) float64 {
+		return (1.0 / (81.0 * math.SqrtPi)) * math.Pow(1.0/a, 3.0/2.0) * (6*rho/a - (rho*rho)/(a*a)) * math.Exp(-rho/(3*a)) * math.Sin(theta) * math.Cos(float64(m)*phi)
+	}
+}
+
+// psi2PxOrbital returns the psi function of the real 2p_x-orbital (n=2, l=1,
+// m=+1) with nuclear charge Z.
+//
+// ref: https://winter.group.shef.ac.uk/orbitron/AOs/2p/equations.html
+func psi2PxOrbital(Z int) PsiFunc {
+	// a is the reduced Bohr radius a_0/Z, and rho is the radius.
+	a := BohrRadius / float64(Z)
+	return func(rho, theta, phi float64) float64 {
+		return (1.0 / (math.Sqrt(32) * math.SqrtPi)) * math.Pow(1.0/a, 3.0/2.0) * (rho / a) * math.Exp(-rho/(2*a)) * math.Sin(theta) * math.Cos(phi)
+	}
+}
+
+// psi2PyOrbital returns the psi function of the real 2p_y-orbital (n=2, l=1,
+// m=-1) with nuclear charge Z.
+//
+// ref: https://winter.group.shef.ac.uk/orbitron/AOs/2p/equations.html
+func psi2PyOrbital(Z int) PsiFunc {
+	// a is the reduced Bohr radius a_0/Z, and rho is the radius.
+	a := BohrRadius / float64(Z)
+	return func(rho, theta, phi float64) float64 {
+		return (1.0 / (math.Sqrt(32) * math.SqrtPi)) * math.Pow(1.0/a, 3.0/2.0) * (rho / a) * math.Exp(-rho/(2*a)) * math.Sin(theta) * math.Sin(phi)
+	}
+}
+
+// === [ d-orbitals ] ==========================================================
+
+// psi3DOrbital returns the psi function of the 3d-orbitals (n=3, l=2,
+// m={-2,-1,0,1,2}) with nuclear charge Z.
+//
+// ref: https://chemistrygod.com/atomic-orbital#d-orbital
+func psi3DOrbital(Z, m int) PsiFunc {
+	// a is the reduced Bohr radius a_0/Z, and rho is the radius.
+	a := BohrRadius / float64(Z)
+	switch m {
+	case 0:
+		// 3d-orbital (n=3, l=2, m=0)
+		return func(rho, theta, phi float64) float64 {
+			return (1.0 / (81.0 * math.Sqrt(6) * math.SqrtPi)) * math.Pow(1.0/a, 3.0/2.0) * (rho / a) * (rho / a) * math.Exp(-rho/(3*a)) * (3*math.Cos(theta)*math.Cos(theta) - 1)
+		}
+	case -1, +1:
+		// 3d-orbitals (n=3, l=2, m=+-1)
+		return func(rho, theta, phi float64) float64 {
+			return (1.0 / (81.0 * math.SqrtPi)) * math.Pow(1.0/a, 3.0/2.0) * (rho / a) * (rho / a) * math.Exp(-rho/(3*a)) * math.Sin(theta) * math.Cos(theta) * math.Cos(float64(m)*phi)
+		}
+	}
+	// 3d-orbitals (n=3, l=2, m=+-2)
+	return func(rho, theta, phi float64) float64 {
+		return (1.0 / (162.0 * math.SqrtPi)) * math.Pow(1.0/a, 3.0/2.0) * (rho / a) * (rho / a) * math.Exp(-rho/(3*a)) * math.Sin(theta) * math.Sin(theta) * math.Cos(float64(m)*phi)
+	}
+}
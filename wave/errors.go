@@ -0,0 +1,71 @@
+package wave
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// InvalidQuantumNumberError is returned for quantum numbers (or nuclear charge)
+// outside of their physically valid range (e.g. l >= n).
+//
+// Use errors.As to check for invalid quantum numbers.
+type InvalidQuantumNumberError struct {
+	// Name of the quantum number (Z, n, l or m).
+	Name string
+	// Value of the quantum number.
+	Value int
+	// Valid range of the quantum number (e.g. "0 <= l < n").
+	Range string
+}
+
+// Error returns the error message of the invalid quantum number.
+func (e *InvalidQuantumNumberError) Error() string {
+	return fmt.Sprintf("invalid %s; expected %s, got %d", e.Name, e.Range, e.Value)
+}
+
+// UnsupportedQuantumNumberError is returned for valid quantum numbers not
+// supported by a wave function (e.g. n beyond the range of numerically stable
+// evaluation).
+//
+// Use errors.As to check for unsupported quantum numbers.
+type UnsupportedQuantumNumberError struct {
+	// Name of the quantum number (Z, n, l or m).
+	Name string
+	// Value of the quantum number.
+	Value int
+	// Supported range of the quantum number (e.g. "n <= 100").
+	Range string
+}
+
+// Error returns the error message of the unsupported quantum number.
+func (e *UnsupportedQuantumNumberError) Error() string {
+	return fmt.Sprintf("support for %s=%d not yet implemented; expected %s", e.Name, e.Value, e.Range)
+}
+
+// MaxN is the maximum supported principal quantum number. Beyond MaxN, the
+// radial functions of high l overflow in double precision.
+const MaxN = 100
+
+// CheckQuantumNumbers reports an error if the specified nuclear charge, Z,
+// principal quantum number, n, azimuthal quantum number, l, or magnetic quantum
+// number, m, is invalid (see InvalidQuantumNumberError) or unsupported (see
+// UnsupportedQuantumNumberError).
+func CheckQuantumNumbers(Z, n, l, m int) error {
+	if !(Z >= 1) {
+		return errors.WithStack(&InvalidQuantumNumberError{Name: "Z", Value: Z, Range: "Z >= 1"})
+	}
+	if !(n >= 1) {
+		return errors.WithStack(&InvalidQuantumNumberError{Name: "n", Value: n, Range: "n >= 1"})
+	}
+	if !(0 <= l && l < n) {
+		return errors.WithStack(&InvalidQuantumNumberError{Name: "l", Value: l, Range: "0 <= l < n"})
+	}
+	if !(-l <= m && m <= l) {
+		return errors.WithStack(&InvalidQuantumNumberError{Name: "m", Value: m, Range: "-l <= m <= +l"})
+	}
+	if !(n <= MaxN) {
+		return errors.WithStack(&UnsupportedQuantumNumberError{Name: "n", Value: n, Range: fmt.Sprintf("n <= %d", MaxN)})
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package wave
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// === [ Hybrid wave functions ] ===============================================
+
+// realOrbitals returns the real hydrogen-like orbitals with nuclear charge Z and
+// the specified (n, l, m)-quantum numbers.
+func realOrbitals(Z int, orbitals [][3]int) []*Hydrogenic {
+	var os []*Hydrogenic
+	for _, o := range orbitals {
+		os = append(os, &Hydrogenic{Z: Z, N: o[0], L: o[1], M: o[2], Basis: RealBasis})
+	}
+	return os
+}
+
+// psiFuncs returns the real-valued psi functions of the given hybrid orbitals.
+func psiFuncs(hs []*Combination) []PsiFunc {
+	var Psis []PsiFunc
+	for _, h := range hs {
+		h := h
+		Psis = append(Psis, func(rho, theta, phi float64) float64 {
+			return real(h.Psi(rho, theta, phi))
+		})
+	}
+	return Psis
+}
+
+// Real 2s-, 2p_x-, 2p_y- and 2p_z-orbitals as (n, l, m)-quantum numbers.
+var (
+	orbital2s  = [3]int{2, 0, 0}
+	orbital2px = [3]int{2, 1, +1} // x: m=+1
+	orbital2py = [3]int{2, 1, -1} // y: m=-1
+	orbital2pz = [3]int{2, 1, 0}  // z: m=0
+)
+
+// --- [ sp hybrid wave function ] ---------------------------------------------
+
+// spOrbitals and spCoeffs specify the sp hybrid orbitals, as linear combinations
+// of the real 2s- and 2p_z-orbitals.
+//
+// ref: https://winter.group.shef.ac.uk/orbitron/AO-hybrids/sp/equations.html
+var (
+	spOrbitals = [][3]int{orbital2s, orbital2pz}
+	spCoeffs   = [][]float64{
+		// sp_1
+		{1.0 / math.Sqrt2, +1.0 / math.Sqrt2},
+		// sp_2
+		{1.0 / math.Sqrt2, -1.0 / math.Sqrt2},
+	}
+)
+
+// --- [ sp^2 hybrid wave function ] -------------------------------------------
+
+// sp2Orbitals and sp2Coeffs specify the sp^2 hybrid orbitals, as linear
+// combinations of the real 2s-, 2p_x- and 2p_y-orbitals.
+//
+// ref: https://winter.group.shef.ac.uk/orbitron/AO-hybrids/sp2/equations.html
+var (
+	sp2Orbitals = [][3]int{orbital2s, orbital2px, orbital2py}
+	sp2Coeffs   = [][]float64{
+		// sp^2_1
+		{1.0 / math.Sqrt(3), math.Sqrt(2.0 / 3.0), 0},
+		// sp^2_2
+		{1.0 / math.Sqrt(3), -1.0 / math.Sqrt(6), +1.0 / math.Sqrt2},
+		// sp^2_3
+		{1.0 / math.Sqrt(3), -1.0 / math.Sqrt(6), -1.0 / math.Sqrt2},
+	}
+)
+
+// --- [ sp^3 hybrid wave function ] -------------------------------------------
+
+// sp3Orbitals and sp3Coeffs specify the sp^3 hybrid orbitals, as linear
+// combinations of the real 2s-, 2p_x-, 2p_y- and 2p_z-orbitals.
+//
+// ref: https://winter.group.shef.ac.uk/orbitron/AO-hybrids/sp3/equations.html
+var (
+	sp3Orbitals = [][3]int{orbital2s, orbital2px, orbital2py, orbital2pz}
+	sp3Coeffs   = [][]float64{
+		// sp^3_1
+		{0.5, +0.5, +0.5, +0.5},
+		// sp^3_2
+		{0.5, +0.5, -0.5, -0.5},
+		// sp^3_3
+		{0.5, -0.5, +0.5, -0.5},
+		// sp^3_4
+		{0.5, -0.5, -0.5, +0.5},
+	}
+)
+
+// --- [ sp^3d hybrid wave function ] ------------------------------------------
+
+// sp3dOrbitals and sp3dDirs specify the trigonal bipyramidal sp^3d hybrid
+// orbitals, as linear combinations of the real 3s-, 3p- and 3d_z2-orbitals.
+var (
+	sp3dOrbitals = [][3]int{{3, 0, 0}, {3, 1, +1}, {3, 1, -1}, {3, 1, 0}, {3, 2, 0}}
+	sp3dDirs     = [][3]float64{
+		// Axial.
+		{0, 0, +1},
+		{0, 0, -1},
+		// Equatorial.
+		{1, 0, 0},
+		{-0.5, +math.Sqrt(3) / 2, 0},
+		{-0.5, -math.Sqrt(3) / 2, 0},
+	}
+)
+
+// --- [ sp^3d^2 hybrid wave function ] ----------------------------------------
+
+// sp3d2Orbitals and sp3d2Dirs specify the octahedral sp^3d^2 hybrid orbitals,
+// as linear combinations of the real 3s-, 3p-, 3d_z2- and 3d_x2-y2-orbitals.
+var (
+	sp3d2Orbitals = [][3]int{{3, 0, 0}, {3, 1, +1}, {3, 1, -1}, {3, 1, 0}, {3, 2, 0}, {3, 2, +2}}
+	sp3d2Dirs     = [][3]float64{
+		{+1, 0, 0},
+		{-1, 0, 0},
+		{0, +1, 0},
+		{0, -1, 0},
+		{0, 0, +1},
+		{0, 0, -1},
+	}
+)
+
+// --- [ dsp^2 hybrid wave function ] ------------------------------------------
+
+// dsp2Orbitals and dsp2Dirs specify the square planar dsp^2 hybrid orbitals,
+// as linear combinations of the real 3d_x2-y2-, 4s-, 4p_x- and 4p_y-orbitals.
+var (
+	dsp2Orbitals = [][3]int{{3, 2, +2}, {4, 0, 0}, {4, 1, +1}, {4, 1, -1}}
+	dsp2Dirs     = [][3]float64{
+		{+1, 0, 0},
+		{0, +1, 0},
+		{-1, 0, 0},
+		{0, -1, 0},
+	}
+)
+
+// hybrids lists the supported hybridizations, and the orbitals of their hybrid
+// orbitals; specified either by coefficients or by target directions (see
+// DirectedHybrids).
+var hybrids = []struct {
+	// Hybridization (e.g. "sp3" for sp^3).
+	name string
+	// Real (n, l, m)-orbitals of the linear combinations.
+	orbitals [][3]int
+	// Coefficients of the orbitals, one row per hybrid orbital.
+	coeffs [][]float64
+	// Target directions, one per hybrid orbital; used if coeffs is nil.
+	dirs [][3]float64
+}{
+	{name: "sp", orbitals: spOrbitals, coeffs: spCoeffs},
+	{name: "sp2", orbitals: sp2Orbitals, coeffs: sp2Coeffs},
+	{name: "sp3", orbitals: sp3Orbitals, coeffs: sp3Coeffs},
+	{name: "sp3d", orbitals: sp3dOrbitals, dirs: sp3dDirs},
+	{name: "sp3d2", orbitals: sp3d2Orbitals, dirs: sp3d2Dirs},
+	{name: "dsp2", orbitals: dsp2Orbitals, dirs: dsp2Dirs},
+}
+
+// Hybridizations returns the names of the supported hybridizations (e.g.
+// "sp3" for sp^3).
+func Hybridizations() []string {
+	var names []string
+	for _, h := range hybrids {
+		names = append(names, h.name)
+	}
+	return names
+}
+
+// Hybrids returns the hybrid orbitals of the specified hybridization (e.g.
+// "sp3" or "sp^3d^2") with nuclear charge Z, as linear combinations of real
+// hydrogen-like orbitals. The hybrid orbitals are verified to be orthonormal.
+func Hybrids(hybrid string, Z int) ([]*Combination, error) {
+	if !(Z >= 1) {
+		return nil, errors.WithStack(&InvalidQuantumNumberError{Name: "Z", Value: Z, Range: "Z >= 1"})
+	}
+	name := strings.Replace(hybrid, "^", "", -1)
+	for _, h := range hybrids {
+		if h.name == name {
+			os := realOrbitals(Z, h.orbitals)
+			if h.coeffs == nil {
+				return DirectedHybrids(h.name, os, h.dirs)
+			}
+			var ws []Wavefunction
+			for _, o := range os {
+				ws = append(ws, o)
+			}
+			return NewHybrids(h.name, ws, h.coeffs)
+		}
+	}
+	return nil, errors.Errorf("support for %q hybrid orbitals not yet implemented; expected one of %s", hybrid, strings.Join(Hybridizations(), ", "))
+}
+
+// HybridOrbitals returns the psi functions of the hybrid orbitals of the
+// specified hybridization (e.g. "sp3" or "sp^3d^2") with nuclear charge Z.
+func HybridOrbitals(hybrid string, Z int) ([]PsiFunc, error) {
+	hs, err := Hybrids(hybrid, Z)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return psiFuncs(hs), nil
+}
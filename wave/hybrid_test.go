@@ -0,0 +1,93 @@
+package wave
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDirectedHybrids(t *testing.T) {
+	// Expected s-, p- and d-character of each hybrid orbital, in order.
+	type character struct{ s, p, d float64 }
+	var (
+		// Löwdin orthonormalization of the trigonal bipyramid distributes the
+		// s-character unequally over axial and equatorial hybrid orbitals.
+		axial       = character{s: 0.160, p: 0.500, d: 0.340}
+		equatorial  = character{s: 0.227, p: 0.667, d: 0.106}
+		octahedral  = character{s: 1.0 / 6, p: 1.0 / 2, d: 1.0 / 3}
+		squarePlane = character{s: 1.0 / 4, p: 1.0 / 2, d: 1.0 / 4}
+	)
+	golden := []struct {
+		hybrid string
+		dirs   [][3]float64
+		want   []character
+	}{
+		{hybrid: "sp3d", dirs: sp3dDirs, want: []character{axial, axial, equatorial, equatorial, equatorial}},
+		{hybrid: "sp3d2", dirs: sp3d2Dirs, want: []character{octahedral, octahedral, octahedral, octahedral, octahedral, octahedral}},
+		{hybrid: "dsp2", dirs: dsp2Dirs, want: []character{squarePlane, squarePlane, squarePlane, squarePlane}},
+	}
+	for _, g := range golden {
+		hs, err := Hybrids(g.hybrid, 1)
+		if err != nil {
+			t.Errorf("%s: unable to create hybrid orbitals; %v", g.hybrid, err)
+			continue
+		}
+		var ws []Wavefunction
+		for _, h := range hs {
+			ws = append(ws, h)
+		}
+		if err := CheckOrthonormal(ws); err != nil {
+			t.Errorf("%s: %v", g.hybrid, err)
+		}
+		if len(hs) != len(g.want) {
+			t.Errorf("%s: number of hybrid orbitals mismatch; expected %d, got %d", g.hybrid, len(g.want), len(hs))
+			continue
+		}
+		for i, h := range hs {
+			// Character of the hybrid orbital.
+			var got character
+			for j, term := range h.Terms {
+				c := real(h.Coeffs[j]) * real(h.Coeffs[j])
+				switch _, _, l, _ := term.QuantumNumbers(); l {
+				case 0:
+					got.s += c
+				case 1:
+					got.p += c
+				case 2:
+					got.d += c
+				}
+			}
+			want := g.want[i]
+			if math.Abs(got.s-want.s) > 1e-3 || math.Abs(got.p-want.p) > 1e-3 || math.Abs(got.d-want.d) > 1e-3 {
+				t.Errorf("%s: s/p/d-character mismatch; expected %.3f/%.3f/%.3f, got %.3f/%.3f/%.3f", h.Label(), want.s, want.p, want.d, got.s, got.p, got.d)
+			}
+			// Direction of the maximum of psi within the innermost lobe; beyond the
+			// radial nodes of the ns- and np-orbitals, the sign of the p-character
+			// flips.
+			const r = 2 * BohrRadius
+			theta, phi := maxDirection(h, r)
+			x, y, z := CartesianFromSpherical(1, theta, phi)
+			d := g.dirs[i]
+			norm := math.Sqrt(d[0]*d[0] + d[1]*d[1] + d[2]*d[2])
+			cos := (x*d[0] + y*d[1] + z*d[2]) / norm
+			if angle := math.Acos(math.Min(1, cos)) * 180 / math.Pi; angle > 3 {
+				t.Errorf("%s: direction of maximum mismatch; expected %v, got (%.3f, %.3f, %.3f), %.1f° apart", h.Label(), d, x, y, z, angle)
+			}
+		}
+	}
+}
+
+// maxDirection returns the direction (theta, phi) of the maximum of the real
+// part of psi on the sphere of radius r, on a grid of 2° steps.
+func maxDirection(w Wavefunction, r float64) (theta, phi float64) {
+	const step = 2 * math.Pi / 180
+	max := math.Inf(-1)
+	for i := 0; i <= 90; i++ {
+		for j := 0; j < 180; j++ {
+			t, p := float64(i)*step, float64(j)*step
+			if v := real(w.Psi(r, t, p)); v > max {
+				theta, phi, max = t, p, v
+			}
+		}
+	}
+	return theta, phi
+}
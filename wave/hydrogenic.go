@@ -0,0 +1,196 @@
+package wave
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// === [ Hydrogen-like wave functions ] ========================================
+
+// psiOrbital returns the psi function of the hydrogen-like (n, l, m)-orbital
+// with nuclear charge Z, as built from the radial function R_{nl}(r) and the
+// spherical harmonic Y_l^m(θ, φ).
+//
+// ref: https://en.wikipedia.org/wiki/Hydrogen-like_atom#Non-relativistic_wavefunction_and_energy
+func psiOrbital(Z, n, l, m int) PsiFunc {
+	return func(rho, theta, phi float64) float64 {
+		return radialFunc(Z, n, l, rho) * real(sphericalHarmonic(l, m, theta, phi))
+	}
+}
+
+// psiComplexOrbital returns the complex-valued psi function of the
+// hydrogen-like (n, l, m)-orbital with nuclear charge Z, retaining the e^{imφ}
+// phase of the spherical harmonic Y_l^m(θ, φ).
+func psiComplexOrbital(Z, n, l, m int) ComplexPsiFunc {
+	return func(rho, theta, phi float64) complex128 {
+		return complex(radialFunc(Z, n, l, rho), 0) * sphericalHarmonic(l, m, theta, phi)
+	}
+}
+
+// radialFunc returns the normalized radial function R_{nl}(r) of the
+// hydrogen-like orbital with nuclear charge Z, principal quantum number, n, and
+// azimuthal quantum number, l, at the radius r.
+//
+//    R_{nl}(r) = sqrt((2/(n a))^3 (n-l-1)!/(2n (n+l)!)) e^{-ρ/2} ρ^l L_{n-l-1}^{2l+1}(ρ)
+//
+// where ρ = 2r/(n a), and a = a_0/Z is the reduced Bohr radius.
+func radialFunc(Z, n, l int, r float64) float64 {
+	a := BohrRadius / float64(Z)
+	x := 2.0 * r / (float64(n) * a)
+	// Compute the factorials in log-space to avoid overflow for large n.
+	lnorm := 3.0*math.Log(2.0/(float64(n)*a)) + lgamma(n-l) - math.Log(2.0*float64(n)) - lgamma(n+l+1)
+	return math.Exp(lnorm/2.0-x/2.0) * math.Pow(x, float64(l)) * assocLaguerre(n-l-1, 2*l+1, x)
+}
+
+// sphericalHarmonic returns the normalized spherical harmonic Y_l^m(θ, φ) at
+// the inclination theta and azimuth phi.
+//
+// NOTE: the Condon-Shortley phase (-1)^m is omitted, to be consistent with the
+// closed forms of the 2p-, 3p- and 3d-orbitals.
+func sphericalHarmonic(l, m int, theta, phi float64) complex128 {
+	absM := m
+	if absM < 0 {
+		absM = -absM
+	}
+	lnorm := math.Log(float64(2*l+1)/(4.0*math.Pi)) + lgamma(l-absM+1) - lgamma(l+absM+1)
+	y := math.Exp(lnorm/2.0) * assocLegendre(l, absM, math.Cos(theta))
+	return complex(y, 0) * cmplx.Exp(complex(0, float64(m)*phi))
+}
+
+// assocLaguerre returns the generalized Laguerre polynomial L_k^α(x), using
+// the recurrence relation
+//
+//    (i+1) L_{i+1}^α(x) = (2i+1+α-x) L_i^α(x) - (i+α) L_{i-1}^α(x)
+//
+// ref: https://en.wikipedia.org/wiki/Laguerre_polynomials#Generalized_Laguerre_polynomials
+func assocLaguerre(k, alpha int, x float64) float64 {
+	a := float64(alpha)
+	prev, cur := 1.0, 1.0+a-x // L_0^α, L_1^α
+	if k == 0 {
+		return prev
+	}
+	for i := 1; i < k; i++ {
+		fi := float64(i)
+		prev, cur = cur, ((2.0*fi+1.0+a-x)*cur-(fi+a)*prev)/(fi+1.0)
+	}
+	return cur
+}
+
+// assocLegendre returns the associated Legendre polynomial P_l^m(x) for 0 <= m
+// <= l, without the Condon-Shortley phase (-1)^m, using the recurrence relation
+//
+//    (i-m) P_i^m(x) = (2i-1) x P_{i-1}^m(x) - (i+m-1) P_{i-2}^m(x)
+//
+// ref: https://en.wikipedia.org/wiki/Associated_Legendre_polynomials#Recurrence_formula
+func assocLegendre(l, m int, x float64) float64 {
+	// P_m^m(x) = (2m-1)!! (1-x^2)^{m/2}
+	pmm := 1.0
+	s := math.Sqrt((1.0 - x) * (1.0 + x))
+	for i := 1; i <= m; i++ {
+		pmm *= float64(2*i-1) * s
+	}
+	if l == m {
+		return pmm
+	}
+	// P_{m+1}^m(x) = x (2m+1) P_m^m(x)
+	pmm1 := x * float64(2*m+1) * pmm
+	for i := m + 2; i <= l; i++ {
+		pmm, pmm1 = pmm1, (float64(2*i-1)*x*pmm1-float64(i+m-1)*pmm)/float64(i-m)
+	}
+	return pmm1
+}
+
+// lgamma returns the natural logarithm of Γ(x) = (x-1)!.
+func lgamma(x int) float64 {
+	v, _ := math.Lgamma(float64(x))
+	return v
+}
+
+// === [ Real hydrogen-like wave functions ] ===================================
+
+// psiRealOrbital returns the psi function of the real hydrogen-like (n, l,
+// m)-orbital with nuclear charge Z, as built from the radial function
+// R_{nl}(r) and the real (tesseral) spherical harmonic S_l^m(θ, φ).
+//
+// Real orbitals with m > 0 have cos(mφ) dependence (e.g. p_x, d_xz, d_x²−y²),
+// and real orbitals with m < 0 have sin(|m|φ) dependence (e.g. p_y, d_yz,
+// d_xy).
+//
+// ref: https://en.wikipedia.org/wiki/Atomic_orbital#Real_orbitals
+func psiRealOrbital(Z, n, l, m int) PsiFunc {
+	return func(rho, theta, phi float64) float64 {
+		return radialFunc(Z, n, l, rho) * realSphericalHarmonic(l, m, theta, phi)
+	}
+}
+
+// realSphericalHarmonic returns the normalized real (tesseral) spherical
+// harmonic S_l^m(θ, φ) at the inclination theta and azimuth phi.
+//
+//    S_l^m(θ, φ) = √2 N_l^|m| P_l^|m|(cos θ) cos(mφ)    if m > 0
+//    S_l^m(θ, φ) =    N_l^0   P_l^0(cos θ)             if m = 0
+//    S_l^m(θ, φ) = √2 N_l^|m| P_l^|m|(cos θ) sin(|m|φ)  if m < 0
+//
+// NOTE: the Condon-Shortley phase (-1)^m is omitted, so that the positive lobe
+// of p_x points along the positive x-axis.
+func realSphericalHarmonic(l, m int, theta, phi float64) float64 {
+	y := sphericalHarmonic(l, m, theta, phi)
+	switch {
+	case m > 0:
+		return math.Sqrt2 * real(y)
+	case m < 0:
+		// Y_l^{-|m|} has e^{-i|m|φ} dependence.
+		return -math.Sqrt2 * imag(y)
+	default:
+		return real(y)
+	}
+}
+
+// realOrbitalNames maps from azimuthal quantum number, l, and magnetic quantum
+// number, m+l, to the name of the corresponding real orbital.
+var realOrbitalNames = [][]string{
+	// s-orbitals (l=0)
+	{"s"},
+	// p-orbitals (l=1)
+	{"p_y", "p_z", "p_x"},
+	// d-orbitals (l=2)
+	{"d_xy", "d_yz", "d_z2", "d_xz", "d_x2-y2"},
+	// f-orbitals (l=3)
+	{"f_y(3x2-y2)", "f_xyz", "f_yz2", "f_z3", "f_xz2", "f_z(x2-y2)", "f_x(x2-3y2)"},
+}
+
+// RealOrbitalName returns the name (e.g. "d_xy") of the real orbital with the
+// specified azimuthal quantum number, l, and magnetic quantum number, m. Real
+// orbitals without conventional names are named by subshell and m (e.g.
+// "g_m-3").
+func RealOrbitalName(l, m int) string {
+	if l < len(realOrbitalNames) {
+		return realOrbitalNames[l][m+l]
+	}
+	return fmt.Sprintf("%c_m%d", SubshellLetter(l), m)
+}
+
+// RealOrbitalM returns the magnetic quantum number of the real orbital with the
+// specified azimuthal quantum number, l, and name (e.g. "d_xy"). The boolean
+// return value indicates success.
+func RealOrbitalM(l int, name string) (int, bool) {
+	for m := -l; m <= l; m++ {
+		if RealOrbitalName(l, m) == name {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// subshellLetters holds the spectroscopic letters of subshells, indexed by
+// azimuthal quantum number.
+const subshellLetters = "spdfghiklmnoqrtuv"
+
+// SubshellLetter returns the spectroscopic letter (e.g. 'd') of the subshell
+// with the specified azimuthal quantum number, l.
+func SubshellLetter(l int) byte {
+	if l < len(subshellLetters) {
+		return subshellLetters[l]
+	}
+	return '?'
+}
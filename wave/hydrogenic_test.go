@@ -0,0 +1,103 @@
+package wave
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestPsiOrbitalClosedForms(t *testing.T) {
+	golden := []struct {
+		n, l, m int
+		closed  func(Z int) PsiFunc
+	}{
+		{n: 1, l: 0, m: 0, closed: psi1SOrbital},
+		{n: 2, l: 0, m: 0, closed: psi2SOrbital},
+		{n: 3, l: 0, m: 0, closed: psi3SOrbital},
+		{n: 2, l: 1, m: -1, closed: func(Z int) PsiFunc { return psi2POrbital(Z, -1) }},
+		{n: 2, l: 1, m: 0, closed: func(Z int) PsiFunc { return psi2POrbital(Z, 0) }},
+		{n: 2, l: 1, m: 1, closed: func(Z int) PsiFunc { return psi2POrbital(Z, 1) }},
+		{n: 3, l: 1, m: -1, closed: func(Z int) PsiFunc { return psi3POrbital(Z, -1) }},
+		{n: 3, l: 1, m: 0, closed: func(Z int) PsiFunc { return psi3POrbital(Z, 0) }},
+		{n: 3, l: 1, m: 1, closed: func(Z int) PsiFunc { return psi3POrbital(Z, 1) }},
+		{n: 3, l: 2, m: -2, closed: func(Z int) PsiFunc { return psi3DOrbital(Z, -2) }},
+		{n: 3, l: 2, m: -1, closed: func(Z int) PsiFunc { return psi3DOrbital(Z, -1) }},
+		{n: 3, l: 2, m: 0, closed: func(Z int) PsiFunc { return psi3DOrbital(Z, 0) }},
+		{n: 3, l: 2, m: 1, closed: func(Z int) PsiFunc { return psi3DOrbital(Z, 1) }},
+		{n: 3, l: 2, m: 2, closed: func(Z int) PsiFunc { return psi3DOrbital(Z, 2) }},
+	}
+	for _, g := range golden {
+		for _, Z := range []int{1, 2} {
+			name := fmt.Sprintf("Z=%d,n=%d,l=%d,m=%d", Z, g.n, g.l, g.m)
+			got, want := psiOrbital(Z, g.n, g.l, g.m), g.closed(Z)
+			for _, p := range sphericalGrid(20 * BohrRadius / float64(Z)) {
+				x, y := got(p[0], p[1], p[2]), want(p[0], p[1], p[2])
+				if !closeTo(x, y, 1e-12*math.Pow(float64(Z), 1.5)) {
+					t.Errorf("%s: psi mismatch at (rho, theta, phi) = %v; expected %g, got %g", name, p, y, x)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestPsiRealOrbitalClosedForms(t *testing.T) {
+	golden := []struct {
+		n, l, m int
+		name    string
+		closed  func(Z int) PsiFunc
+	}{
+		{n: 2, l: 1, m: +1, name: "p_x", closed: psi2PxOrbital},
+		{n: 2, l: 1, m: -1, name: "p_y", closed: psi2PyOrbital},
+		{n: 2, l: 1, m: 0, name: "p_z", closed: func(Z int) PsiFunc { return psi2POrbital(Z, 0) }},
+	}
+	for _, g := range golden {
+		if got := RealOrbitalName(g.l, g.m); got != g.name {
+			t.Errorf("l=%d,m=%d: name mismatch; expected %q, got %q", g.l, g.m, g.name, got)
+		}
+		for _, Z := range []int{1, 2} {
+			name := fmt.Sprintf("Z=%d,%d%s", Z, g.n, g.name)
+			got, want := psiRealOrbital(Z, g.n, g.l, g.m), g.closed(Z)
+			for _, p := range sphericalGrid(20 * BohrRadius / float64(Z)) {
+				x, y := got(p[0], p[1], p[2]), want(p[0], p[1], p[2])
+				if !closeTo(x, y, 1e-12*math.Pow(float64(Z), 1.5)) {
+					t.Errorf("%s: psi mismatch at (rho, theta, phi) = %v; expected %g, got %g", name, p, y, x)
+					break
+				}
+			}
+		}
+	}
+	// The 2p_x- and 2p_y-orbitals are distinct; along the x-axis, 2p_y vanishes.
+	px, py := psiRealOrbital(1, 2, 1, +1), psiRealOrbital(1, 2, 1, -1)
+	if x, y := px(2, math.Pi/2, 0), py(2, math.Pi/2, 0); !(x > 0.05) || !closeTo(y, 0, 1e-15) {
+		t.Errorf("2p_x and 2p_y mismatch along the x-axis; expected psi > 0 and 0, got %g and %g", x, y)
+	}
+}
+
+// sphericalGrid returns a grid of spherical (rho, theta, phi)-coordinates
+// within the given radius, including the poles.
+func sphericalGrid(rmax float64) [][3]float64 {
+	const (
+		nr     = 24
+		ntheta = 9
+		nphi   = 12
+	)
+	var ps [][3]float64
+	for i := 0; i <= nr; i++ {
+		rho := rmax * float64(i) / nr
+		for j := 0; j < ntheta; j++ {
+			theta := math.Pi * float64(j) / (ntheta - 1)
+			for k := 0; k < nphi; k++ {
+				phi := 2 * math.Pi * float64(k) / nphi
+				ps = append(ps, [3]float64{rho, theta, phi})
+			}
+		}
+	}
+	return ps
+}
+
+// closeTo reports whether x and y are equal within the given absolute
+// tolerance.
+func closeTo(x, y, tol float64) bool {
+	return math.Abs(x-y) <= tol
+}
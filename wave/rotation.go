@@ -0,0 +1,231 @@
+package wave
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// === [ Rotation of wave functions ] ==========================================
+
+// Rotation is a proper rotation of Cartesian (x, y, z)-coordinates, as a 3x3
+// rotation matrix acting on column vectors.
+type Rotation [3][3]float64
+
+// EulerRotation returns the rotation with the specified Euler angles, in the
+// z-y-z convention; i.e. R = R_z(alpha) R_y(beta) R_z(gamma).
+func EulerRotation(alpha, beta, gamma float64) Rotation {
+	rz := func(a float64) Rotation {
+		return Rotation{
+			{math.Cos(a), -math.Sin(a), 0},
+			{math.Sin(a), math.Cos(a), 0},
+			{0, 0, 1},
+		}
+	}
+	ry := Rotation{
+		{math.Cos(beta), 0, math.Sin(beta)},
+		{0, 1, 0},
+		{-math.Sin(beta), 0, math.Cos(beta)},
+	}
+	return rz(alpha).mul(ry).mul(rz(gamma))
+}
+
+// NewRotation returns the rotation of the given rotation matrix.
+//
+// An error is returned if the matrix is not orthogonal with determinant 1.
+func NewRotation(m [3][3]float64) (Rotation, error) {
+	const tolerance = 1e-9
+	rot := Rotation(m)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			// (R R^T)_{ij} = δ_ij
+			var sum float64
+			for k := 0; k < 3; k++ {
+				sum += m[i][k] * m[j][k]
+			}
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if math.Abs(sum-want) > tolerance {
+				return Rotation{}, errors.Errorf("invalid rotation matrix; expected orthogonal matrix, got %v", m)
+			}
+		}
+	}
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) - m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) + m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+	if math.Abs(det-1) > tolerance {
+		return Rotation{}, errors.Errorf("invalid rotation matrix; expected determinant 1, got %g", det)
+	}
+	return rot, nil
+}
+
+// mul returns the matrix product rot b.
+func (rot Rotation) mul(b Rotation) Rotation {
+	var c Rotation
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				c[i][j] += rot[i][k] * b[k][j]
+			}
+		}
+	}
+	return c
+}
+
+// EulerAngles returns the Euler angles of the rotation in the z-y-z convention
+// (see EulerRotation). For rotations with beta = 0 or beta = π, gamma is 0.
+func (rot Rotation) EulerAngles() (alpha, beta, gamma float64) {
+	beta = math.Acos(math.Max(-1, math.Min(1, rot[2][2])))
+	switch {
+	case math.Abs(math.Sin(beta)) > 1e-12:
+		alpha = math.Atan2(rot[1][2], rot[0][2])
+		gamma = math.Atan2(rot[2][1], -rot[2][0])
+	case rot[2][2] > 0:
+		// R = R_z(alpha)
+		alpha = math.Atan2(rot[1][0], rot[0][0])
+	default:
+		// R = R_z(alpha) R_y(π)
+		alpha = math.Atan2(-rot[1][0], rot[1][1])
+	}
+	return alpha, beta, gamma
+}
+
+// WignerD returns the Wigner D-matrix D^l_{m'm} of the rotation with the
+// specified Euler angles (see EulerRotation), indexed by m'+l and m+l. The
+// D-matrix is expressed in the basis of the spherical harmonics Y_l^m (without
+// the Condon-Shortley phase), such that the rotated spherical harmonic is
+//
+//    Y_l^m(R^{-1} r) = sum_{m'} Y_l^{m'}(r) D^l_{m'm}
+//
+// ref: https://en.wikipedia.org/wiki/Wigner_D-matrix
+func WignerD(l int, alpha, beta, gamma float64) [][]complex128 {
+	// phase returns the Condon-Shortley phase (-1)^m omitted by Y_l^m.
+	phase := func(m int) float64 {
+		if m > 0 && m%2 == 1 {
+			return -1
+		}
+		return 1
+	}
+	d := make([][]complex128, 2*l+1)
+	for mp := -l; mp <= l; mp++ {
+		d[mp+l] = make([]complex128, 2*l+1)
+		for m := -l; m <= l; m++ {
+			v := phase(mp) * phase(m) * wignerSmallD(l, mp, m, beta)
+			d[mp+l][m+l] = complex(v, 0) * cmplx.Exp(complex(0, -float64(mp)*alpha-float64(m)*gamma))
+		}
+	}
+	return d
+}
+
+// wignerSmallD returns the Wigner (small) d-matrix element d^l_{m'm}(β).
+//
+//    d^l_{m'm}(β) = sum_s (-1)^{m'-m+s} ((l+m')! (l-m')! (l+m)! (l-m)!)^{1/2} /
+//                   ((l+m-s)! s! (m'-m+s)! (l-m'-s)!)
+//                   cos(β/2)^{2l+m-m'-2s} sin(β/2)^{m'-m+2s}
+func wignerSmallD(l, mp, m int, beta float64) float64 {
+	lf := func(k int) float64 { return lgamma(k + 1) }
+	c, s := math.Cos(beta/2), math.Sin(beta/2)
+	lnorm := (lf(l+mp) + lf(l-mp) + lf(l+m) + lf(l-m)) / 2
+	smin, smax := 0, l+m
+	if m-mp > smin {
+		smin = m - mp
+	}
+	if l-mp < smax {
+		smax = l - mp
+	}
+	var sum float64
+	for k := smin; k <= smax; k++ {
+		v := math.Exp(lnorm-lf(l+m-k)-lf(k)-lf(mp-m+k)-lf(l-mp-k)) * math.Pow(c, float64(2*l+m-mp-2*k)) * math.Pow(s, float64(mp-m+2*k))
+		if (mp-m+k)%2 != 0 {
+			v = -v
+		}
+		sum += v
+	}
+	return sum
+}
+
+// Rotate returns the wave function rotated by rot; i.e. psi'(r) = psi(R^{-1} r),
+// using Wigner D-matrices to rotate each (n, l)-subspace exactly. Wave
+// functions in the real basis (e.g. real orbitals and hybrid orbitals) remain
+// in the real basis.
+//
+// An error is returned if the wave function is not a linear combination of
+// hydrogen-like orbitals, or if it vanishes.
+func Rotate(w Wavefunction, rot Rotation) (*Combination, error) {
+	ts, ok := expand(w)
+	if !ok {
+		return nil, errors.Errorf("support for rotation of %q not yet implemented; expected linear combination of hydrogen-like orbitals", w.Label())
+	}
+	alpha, beta, gamma := rot.EulerAngles()
+	coeffs := make(map[termKey]complex128)
+	ds := make(map[int][][]complex128)
+	for _, t := range ts {
+		d, ok := ds[t.l]
+		if !ok {
+			d = WignerD(t.l, alpha, beta, gamma)
+			ds[t.l] = d
+		}
+		for mp := -t.l; mp <= t.l; mp++ {
+			coeffs[termKey{t.Z, t.n, t.l, mp}] += d[mp+t.l][t.m+t.l] * t.c
+		}
+	}
+	inReal := isRealBasis(w)
+	var keys []termKey
+	for k := range coeffs {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch {
+		case a.n != b.n:
+			return a.n < b.n
+		case a.l != b.l:
+			return a.l < b.l
+		}
+		return a.m < b.m
+	})
+	c := &Combination{Name: fmt.Sprintf("%s_rotated", w.Label())}
+	for _, k := range keys {
+		coeff := coeffs[k]
+		basis := ComplexBasis
+		if inReal {
+			// Coefficient <S_l^m|psi> of the real spherical harmonic S_l^m; real
+			// up to rounding errors.
+			basis = RealBasis
+			if k.m != 0 {
+				cm, cneg := realHarmonicCoeffs(k.m)
+				coeff = cmplx.Conj(cm)*coeffs[k] + cmplx.Conj(cneg)*coeffs[termKey{k.Z, k.n, k.l, -k.m}]
+			}
+			coeff = complex(real(coeff), 0)
+		}
+		if cmplx.Abs(coeff) < 1e-14 {
+			continue
+		}
+		c.Coeffs = append(c.Coeffs, coeff)
+		c.Terms = append(c.Terms, &Hydrogenic{Z: k.Z, N: k.n, L: k.l, M: k.m, Basis: basis})
+	}
+	if len(c.Terms) == 0 {
+		return nil, errors.Errorf("unable to rotate %q; vanishing wave function", w.Label())
+	}
+	return c, nil
+}
+
+// isRealBasis reports whether the wave function is a real linear combination of
+// real hydrogen-like orbitals.
+func isRealBasis(w Wavefunction) bool {
+	switch w := w.(type) {
+	case *Hydrogenic:
+		return w.Basis == RealBasis || w.M == 0
+	case *Combination:
+		for i, t := range w.Terms {
+			if imag(w.Coeffs[i]) != 0 || !isRealBasis(t) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package wave
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestRotate(t *testing.T) {
+	// Permutation (x, y, z) -> (y, z, x) of the axes.
+	perm, err := NewRotation([3][3]float64{
+		{0, 0, 1},
+		{1, 0, 0},
+		{0, 1, 0},
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	rots := []struct {
+		name string
+		rot  Rotation
+	}{
+		{name: "identity", rot: EulerRotation(0, 0, 0)},
+		{name: "z(40°)", rot: EulerRotation(40*math.Pi/180, 0, 0)},
+		{name: "y(90°)", rot: EulerRotation(0, math.Pi/2, 0)},
+		{name: "y(180°)", rot: EulerRotation(0, math.Pi, 0)},
+		{name: "euler(30°,75°,-120°)", rot: EulerRotation(30*math.Pi/180, 75*math.Pi/180, -120*math.Pi/180)},
+		{name: "permutation", rot: perm},
+	}
+	const Z, n = 1, 4
+	var ws []Wavefunction
+	for l := 0; l < n; l++ {
+		for m := -l; m <= l; m++ {
+			for _, basis := range []Basis{RealBasis, ComplexBasis} {
+				w, err := NewHydrogenic(basis, Z, n, l, m)
+				if err != nil {
+					t.Fatalf("%+v", err)
+				}
+				ws = append(ws, w)
+			}
+		}
+	}
+	hs, err := Hybrids("sp3d2", Z)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, h := range hs {
+		ws = append(ws, h)
+	}
+	ps := sphericalGrid(30 * BohrRadius)
+	for _, r := range rots {
+		for _, w := range ws {
+			name := fmt.Sprintf("%s (%s)", w.Label(), r.name)
+			got, err := Rotate(w, r.rot)
+			if err != nil {
+				t.Errorf("%s: unable to rotate; %v", name, err)
+				continue
+			}
+			if isRealBasis(w) && !isRealBasis(got) {
+				t.Errorf("%s: expected rotated wave function in the real basis", name)
+			}
+			for _, p := range ps {
+				// psi'(r) = psi(R^{-1} r), where R^{-1} = R^T.
+				x, y, z := CartesianFromSpherical(p[0], p[1], p[2])
+				var v [3]float64
+				for i := 0; i < 3; i++ {
+					v[i] = r.rot[0][i]*x + r.rot[1][i]*y + r.rot[2][i]*z
+				}
+				rho, theta, phi := SphericalFromCartesian(v[0], v[1], v[2])
+				a, b := got.Psi(p[0], p[1], p[2]), w.Psi(rho, theta, phi)
+				if cmplx.Abs(a-b) > 1e-10 {
+					t.Errorf("%s: psi mismatch at (rho, theta, phi) = %v; expected %g, got %g", name, p, b, a)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestRotateVanishing(t *testing.T) {
+	w, err := NewHydrogenic(RealBasis, 1, 2, 1, 1)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// psi - psi
+	c, err := LinearCombination([]complex128{1, -1}, []Wavefunction{w, w})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, v := range []Wavefunction{c, &Combination{}} {
+		if _, err := Rotate(v, EulerRotation(0, math.Pi/2, 0)); err == nil {
+			t.Errorf("%q: expected error for vanishing wave function, got nil", v.Label())
+		}
+	}
+	// Empty linear combinations have no definite quantum numbers.
+	Z, n, l, m := (&Combination{}).QuantumNumbers()
+	if Z != 0 || n != -1 || l != -1 || m != -1 {
+		t.Errorf("empty linear combination: quantum numbers mismatch; expected (0, -1, -1, -1), got (%d, %d, %d, %d)", Z, n, l, m)
+	}
+	if norm := (&Combination{}).Norm(); norm != 0 {
+		t.Errorf("empty linear combination: norm mismatch; expected 0, got %g", norm)
+	}
+}
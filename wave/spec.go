@@ -0,0 +1,194 @@
+package wave
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Spec is a parsed orbital specification, specifying either an orbital
+// by its quantum numbers and basis, or a set of hybrid orbitals by their
+// hybridization.
+//
+// Examples:
+//
+//    3d_z2          real 3d_z2-orbital (n=3, l=2, m=0)
+//    2p_x           real 2p_x-orbital (n=2, l=1, m=+1)
+//    1s             1s-orbital (n=1, l=0, m=0)
+//    4f-3           complex 4f-orbital with m=-3 (n=4, l=3, m=-3)
+//    n=3,l=2,m=-1   complex orbital with the given quantum numbers
+//    sp3            sp^3 hybrid orbitals (also sp^3)
+//    sp3d2          sp^3d^2 hybrid orbitals (also sp^3d^2)
+type Spec struct {
+	// Hybridization (e.g. "sp3") of hybrid orbitals; empty for orbitals.
+	Hybrid string
+	// Principal quantum number.
+	N int
+	// Azimuthal quantum number.
+	L int
+	// Magnetic quantum number.
+	M int
+	// Basis of the angular part of the orbital.
+	Basis Basis
+}
+
+// String returns the canonical name of the orbital specification (e.g. "3d_z2",
+// "4f-3" or "sp3"), as used in output file names. Orbitals with m=0 are named as
+// real orbitals (e.g. "2p_z") in either basis, since the complex and real m=0
+// orbitals coincide.
+func (spec Spec) String() string {
+	if spec.IsHybrid() {
+		return spec.Hybrid
+	}
+	if spec.Basis == RealBasis || spec.M == 0 {
+		return fmt.Sprintf("%d%s", spec.N, RealOrbitalName(spec.L, spec.M))
+	}
+	if spec.M > 0 {
+		return fmt.Sprintf("%d%c+%d", spec.N, SubshellLetter(spec.L), spec.M)
+	}
+	return fmt.Sprintf("%d%c%d", spec.N, SubshellLetter(spec.L), spec.M)
+}
+
+// IsHybrid reports whether the orbital specification specifies a set of hybrid
+// orbitals.
+func (spec Spec) IsHybrid() bool {
+	return len(spec.Hybrid) > 0
+}
+
+// ParseSpec parses the given orbital specification (see Spec).
+// The quantum numbers of orbitals are validated.
+func ParseSpec(s string) (Spec, error) {
+	switch {
+	case len(s) == 0:
+		return Spec{}, errors.New("invalid orbital; empty orbital specification")
+	case strings.HasPrefix(s, "sp"), strings.HasPrefix(s, "dsp"):
+		return parseHybridSpec(s)
+	case strings.Contains(s, "="):
+		return parseQuantumNumberSpec(s)
+	}
+	return parseSpectroscopicSpec(s)
+}
+
+// parseHybridSpec parses the given hybrid orbital specification (e.g. "sp3",
+// "sp^3" or "dsp^2").
+func parseHybridSpec(s string) (Spec, error) {
+	hybrid := strings.Replace(s, "^", "", -1)
+	for _, h := range hybrids {
+		if hybrid == h.name {
+			return Spec{Hybrid: hybrid}, nil
+		}
+	}
+	return Spec{}, errors.Errorf("invalid hybrid orbitals %q; expected one of %s", s, strings.Join(Hybridizations(), ", "))
+}
+
+// parseQuantumNumberSpec parses the given orbital specification of quantum
+// numbers (e.g. "n=3,l=2,m=-1"). The magnetic quantum number defaults to 0 if
+// omitted. The orbital is in the complex basis.
+func parseQuantumNumberSpec(s string) (Spec, error) {
+	spec := Spec{Basis: ComplexBasis}
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(parts) != 2 {
+			return Spec{}, errors.Errorf("invalid orbital %q; expected key=value pair, got %q", s, field)
+		}
+		key, val := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		x, err := strconv.Atoi(val)
+		if err != nil {
+			return Spec{}, errors.Errorf("invalid orbital %q; invalid integer value %q of %s", s, val, key)
+		}
+		switch key {
+		case "n":
+			spec.N = x
+		case "l":
+			spec.L = x
+		case "m":
+			spec.M = x
+		default:
+			return Spec{}, errors.Errorf("invalid orbital %q; expected quantum number n, l or m, got %q", s, key)
+		}
+		if seen[key] {
+			return Spec{}, errors.Errorf("invalid orbital %q; quantum number %s specified more than once", s, key)
+		}
+		seen[key] = true
+	}
+	for _, key := range []string{"n", "l"} {
+		if !seen[key] {
+			return Spec{}, errors.Errorf("invalid orbital %q; missing quantum number %s", s, key)
+		}
+	}
+	if err := checkSpec(s, spec); err != nil {
+		return Spec{}, errors.WithStack(err)
+	}
+	return spec, nil
+}
+
+// parseSpectroscopicSpec parses the given orbital specification in
+// spectroscopic notation; either a real orbital (e.g. "3d_z2" or "2s") or a
+// complex orbital with a magnetic quantum number (e.g. "4f-3" or "2p+1").
+func parseSpectroscopicSpec(s string) (Spec, error) {
+	// Principal quantum number.
+	i := 0
+	for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return Spec{}, errors.Errorf("invalid orbital %q; expected principal quantum number (e.g. 3d_z2, 4f-3, sp3 or n=3,l=2,m=-1)", s)
+	}
+	n, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return Spec{}, errors.Errorf("invalid orbital %q; invalid principal quantum number %q", s, s[:i])
+	}
+	// Azimuthal quantum number.
+	if i == len(s) {
+		return Spec{}, errors.Errorf("invalid orbital %q; missing subshell letter (%s) after principal quantum number", s, subshellLetters)
+	}
+	l := strings.IndexByte(subshellLetters, s[i])
+	if l == -1 {
+		return Spec{}, errors.Errorf("invalid orbital %q; invalid subshell letter %q, expected one of %s", s, s[i], subshellLetters)
+	}
+	spec := Spec{N: n, L: l}
+	// Magnetic quantum number.
+	name, rest := s[i:], s[i+1:]
+	if len(rest) == 0 || rest[0] == '_' {
+		// Real orbital.
+		m, ok := RealOrbitalM(l, name)
+		if !ok {
+			var names []string
+			for m := -l; m <= l; m++ {
+				names = append(names, fmt.Sprintf("%d%s", n, RealOrbitalName(l, m)))
+			}
+			if l == 0 {
+				return Spec{}, errors.Errorf("invalid orbital %q; expected %s", s, names[0])
+			}
+			return Spec{}, errors.Errorf("invalid orbital %q; expected real orbital name (one of %s) or magnetic quantum number (e.g. %d%c-%d)", s, strings.Join(names, ", "), n, SubshellLetter(l), l)
+		}
+		spec.M = m
+		spec.Basis = RealBasis
+	} else {
+		// Complex orbital.
+		m, err := strconv.Atoi(rest)
+		if err != nil {
+			return Spec{}, errors.Errorf("invalid orbital %q; invalid magnetic quantum number %q", s, rest)
+		}
+		spec.M = m
+		spec.Basis = ComplexBasis
+	}
+	if err := checkSpec(s, spec); err != nil {
+		return Spec{}, errors.WithStack(err)
+	}
+	return spec, nil
+}
+
+// checkSpec reports an error if the quantum numbers of the given orbital
+// specification, s, are invalid.
+func checkSpec(s string, spec Spec) error {
+	// The nuclear charge is not part of the specification.
+	const Z = 1
+	if err := CheckQuantumNumbers(Z, spec.N, spec.L, spec.M); err != nil {
+		return errors.Wrapf(err, "invalid orbital %q", s)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package wave
+
+import "testing"
+
+func TestParseSpec(t *testing.T) {
+	golden := []struct {
+		in   string
+		want Spec
+	}{
+		{in: "1s", want: Spec{N: 1, L: 0, M: 0, Basis: RealBasis}},
+		{in: "3d_z2", want: Spec{N: 3, L: 2, M: 0, Basis: RealBasis}},
+		{in: "2p_x", want: Spec{N: 2, L: 1, M: +1, Basis: RealBasis}},
+		{in: "2p_y", want: Spec{N: 2, L: 1, M: -1, Basis: RealBasis}},
+		{in: "4f-3", want: Spec{N: 4, L: 3, M: -3, Basis: ComplexBasis}},
+		{in: "2p+1", want: Spec{N: 2, L: 1, M: +1, Basis: ComplexBasis}},
+		{in: "5g2", want: Spec{N: 5, L: 4, M: +2, Basis: ComplexBasis}},
+		{in: "n=3,l=2,m=-1", want: Spec{N: 3, L: 2, M: -1, Basis: ComplexBasis}},
+		{in: "l=1, n=2", want: Spec{N: 2, L: 1, M: 0, Basis: ComplexBasis}},
+		{in: "sp3", want: Spec{Hybrid: "sp3"}},
+		{in: "sp^3d^2", want: Spec{Hybrid: "sp3d2"}},
+		{in: "dsp^2", want: Spec{Hybrid: "dsp2"}},
+	}
+	for _, g := range golden {
+		got, err := ParseSpec(g.in)
+		if err != nil {
+			t.Errorf("%q: unable to parse orbital specification; %v", g.in, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("%q: orbital specification mismatch; expected %+v, got %+v", g.in, g.want, got)
+		}
+	}
+}
+
+func TestParseSpecInvalid(t *testing.T) {
+	golden := []string{
+		"",
+		"s",
+		"3",
+		"3x",
+		"1p",
+		"2d_xy",
+		"3d_z",
+		"3d_xyz",
+		"2p+2",
+		"2p-x",
+		"n=3",
+		"l=1",
+		"n=3,l=3",
+		"n=3,l=2,m=3",
+		"n=0,l=0",
+		"n=3,n=3,l=1",
+		"n=3,l=1,k=1",
+		"n=3,l=one",
+		"n=3;l=1",
+		"101s",
+		"sp4",
+		"spd",
+	}
+	for _, s := range golden {
+		if spec, err := ParseSpec(s); err == nil {
+			t.Errorf("%q: expected error, got %+v", s, spec)
+		}
+	}
+}
+
+func TestSpecStringRoundTrip(t *testing.T) {
+	var specs []Spec
+	for n := 1; n <= 8; n++ {
+		for l := 0; l < n; l++ {
+			for m := -l; m <= l; m++ {
+				for _, basis := range []Basis{RealBasis, ComplexBasis} {
+					specs = append(specs, Spec{N: n, L: l, M: m, Basis: basis})
+				}
+			}
+		}
+	}
+	for _, name := range Hybridizations() {
+		specs = append(specs, Spec{Hybrid: name})
+	}
+	for _, spec := range specs {
+		s := spec.String()
+		got, err := ParseSpec(s)
+		if err != nil {
+			t.Errorf("%+v: unable to parse %q; %v", spec, s, err)
+			continue
+		}
+		// Complex m=0 orbitals are named as the coinciding real orbitals.
+		want := spec
+		if !spec.IsHybrid() && spec.M == 0 {
+			want.Basis = RealBasis
+		}
+		if got != want {
+			t.Errorf("%q: orbital specification mismatch; expected %+v, got %+v", s, want, got)
+		}
+		if s2 := got.String(); s2 != s {
+			t.Errorf("%+v: name mismatch; expected %q, got %q", got, s, s2)
+		}
+	}
+	// Names are unique.
+	names := make(map[string]Spec)
+	for _, spec := range specs {
+		if !spec.IsHybrid() && spec.M == 0 && spec.Basis == ComplexBasis {
+			continue
+		}
+		s := spec.String()
+		if prev, ok := names[s]; ok {
+			t.Errorf("%q: name of %+v and %+v collide", s, prev, spec)
+		}
+		names[s] = spec
+	}
+}
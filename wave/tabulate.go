@@ -0,0 +1,324 @@
+package wave
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// === [ Tabulated wave functions ] ============================================
+
+// Resolution of the tables of tabulated wave functions; the number of radial
+// samples per reduced Bohr radius a_0/Z, and the number of polar samples per
+// half-period π/(l+1) of the polar part of the spherical harmonics.
+const (
+	radialTableRes = 64
+	polarTableRes  = 512
+)
+
+// Tabulated is a wave function evaluated through tables of its separable terms
+// (see Tabulate).
+type Tabulated struct {
+	// Terms grouped by radial function, sorted by (Z, n, l).
+	groups []tabGroup
+}
+
+// Tabulate returns the given wave function, evaluated through tables of its
+// separable terms; for a linear combination of hydrogen-like orbitals,
+//
+//    psi(r, θ, φ) = sum_i c_i R_{n_i l_i}(r) Θ_{l_i |m_i|}(θ) Φ_{m_i}(φ)
+//
+// the radial functions R_{nl}(r) and the polar parts Θ_{l|m|}(θ) of the
+// spherical harmonics are tabulated once, and are evaluated by cubic
+// interpolation between table entries; the azimuthal parts Φ_m(φ) (e.g. e^{imφ}
+// or cos(mφ)) are evaluated directly. The interpolation error is below 10^{-8}
+// relative to the maximum of each table, and psi is real-valued if the wave
+// function is a real linear combination of real orbitals (see RealBasis).
+//
+// Radial functions are tabulated up to the radius rmax, beyond which they are
+// evaluated directly. The tabulated wave function is safe for concurrent use.
+//
+// An error is returned if the wave function is not a linear combination of
+// hydrogen-like orbitals.
+func Tabulate(w Wavefunction, rmax float64) (*Tabulated, error) {
+	coeffs := make(map[tabKey]complex128)
+	if !tabulateInto(coeffs, w, 1) {
+		return nil, errors.Errorf("support for tabulation of %q not yet implemented; expected linear combination of hydrogen-like orbitals", w.Label())
+	}
+	var keys []tabKey
+	for k := range coeffs {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch {
+		case a.Z != b.Z:
+			return a.Z < b.Z
+		case a.n != b.n:
+			return a.n < b.n
+		case a.l != b.l:
+			return a.l < b.l
+		case a.absM() != b.absM():
+			return a.absM() < b.absM()
+		case a.m != b.m:
+			return a.m < b.m
+		}
+		return a.basis < b.basis
+	})
+	// Tables of polar parts, shared between terms.
+	polars := make(map[[2]int]*table)
+	tab := &Tabulated{}
+	for _, k := range keys {
+		// Terms are sorted by (Z, n, l), so that terms sharing radial functions
+		// are adjacent. The radial functions depend on Z, both directly and
+		// through the table step.
+		if len(tab.groups) == 0 || !tab.groups[len(tab.groups)-1].has(k) {
+			Z, n, l := k.Z, k.n, k.l
+			h := BohrRadius / (float64(Z) * radialTableRes)
+			g := tabGroup{
+				Z: Z,
+				n: n,
+				l: l,
+				radial: newTable(0, rmax, h, func(r float64) float64 {
+					return radialFunc(Z, n, l, r)
+				}),
+			}
+			tab.groups = append(tab.groups, g)
+		}
+		absM := k.absM()
+		pk := [2]int{k.l, absM}
+		if _, ok := polars[pk]; !ok {
+			l := k.l
+			h := math.Pi / float64(polarTableRes*(l+1))
+			polars[pk] = newTable(0, math.Pi, h, func(theta float64) float64 {
+				return polarFunc(l, absM, theta)
+			})
+		}
+		t := tabTerm{
+			tabKey: k,
+			c:      coeffs[k],
+			polar:  polars[pk],
+		}
+		g := &tab.groups[len(tab.groups)-1]
+		g.terms = append(g.terms, t)
+	}
+	return tab, nil
+}
+
+// Psi returns psi at the spherical (rho, theta, phi)-coordinate.
+func (tab *Tabulated) Psi(rho, theta, phi float64) complex128 {
+	var psi complex128
+	for i := range tab.groups {
+		g := &tab.groups[i]
+		psi += complex(g.radialAt(rho), 0) * g.angular(theta, phi)
+	}
+	return psi
+}
+
+// Ray returns psi along the ray from the origin in the direction of the
+// inclination theta and azimuth phi, as a function of the radius (see RayFunc).
+// The angular parts of the terms are evaluated once for the ray, such that
+// evaluating psi along the ray only interpolates the radial tables.
+func (tab *Tabulated) Ray(theta, phi float64) func(rho float64) complex128 {
+	angulars := make([]complex128, len(tab.groups))
+	for i := range tab.groups {
+		angulars[i] = tab.groups[i].angular(theta, phi)
+	}
+	return func(rho float64) complex128 {
+		var psi complex128
+		for i := range tab.groups {
+			if angulars[i] == 0 {
+				continue
+			}
+			psi += complex(tab.groups[i].radialAt(rho), 0) * angulars[i]
+		}
+		return psi
+	}
+}
+
+// tabGroup is a group of terms of a tabulated wave function, sharing the radial
+// function R_{nl}(r) with nuclear charge Z.
+type tabGroup struct {
+	Z, n, l int
+	// Table of the radial function R_{nl}(r).
+	radial *table
+	// Terms of the group, sorted by |m|.
+	terms []tabTerm
+}
+
+// has reports whether the given orbital belongs to the group.
+func (g *tabGroup) has(k tabKey) bool {
+	return k.Z == g.Z && k.n == g.n && k.l == g.l
+}
+
+// radialAt returns the radial function R_{nl}(r) of the group at the radius r.
+func (g *tabGroup) radialAt(r float64) float64 {
+	if v, ok := g.radial.at(r); ok {
+		return v
+	}
+	return radialFunc(g.Z, g.n, g.l, r)
+}
+
+// angular returns the angular part of the group at the inclination theta and
+// azimuth phi; i.e. sum_i c_i Θ_{l |m_i|}(θ) Φ_{m_i}(φ).
+func (g *tabGroup) angular(theta, phi float64) complex128 {
+	// Terms are sorted by |m|, so that terms sharing polar parts and azimuthal
+	// frequencies are adjacent.
+	var (
+		ang      complex128
+		polar    *table
+		p        float64
+		absM     = -1
+		sin, cos float64
+		ok       bool
+	)
+	for _, t := range g.terms {
+		if t.polar != polar {
+			polar = t.polar
+			if p, ok = polar.at(theta); !ok {
+				p = polarFunc(t.l, t.absM(), theta)
+			}
+		}
+		if t.absM() != absM {
+			absM = t.absM()
+			sin, cos = math.Sincos(float64(absM) * phi)
+		}
+		ang += t.c * complex(p, 0) * t.azimuthal(sin, cos)
+	}
+	return ang
+}
+
+// tabKey identifies a hydrogen-like orbital of a tabulated wave function.
+type tabKey struct {
+	Z, n, l, m int
+	basis      Basis
+}
+
+// absM returns the absolute value of the magnetic quantum number.
+func (k tabKey) absM() int {
+	if k.m < 0 {
+		return -k.m
+	}
+	return k.m
+}
+
+// azimuthal returns the azimuthal part Φ_m(φ) of the spherical harmonic of the
+// orbital, such that Y(θ, φ) = Θ_{l|m|}(θ) Φ_m(φ) (see polarFunc), based on
+// sin(|m|φ) and cos(|m|φ).
+//
+//    Φ_m(φ) = e^{imφ}        (complex basis)
+//    Φ_m(φ) = √2 cos(mφ)     (real basis, m > 0)
+//    Φ_m(φ) = √2 sin(|m|φ)   (real basis, m < 0)
+func (k tabKey) azimuthal(sin, cos float64) complex128 {
+	switch {
+	case k.m == 0:
+		return 1
+	case k.basis != RealBasis && k.m < 0:
+		// e^{imφ} = e^{-i|m|φ} for m < 0.
+		return complex(cos, -sin)
+	case k.basis != RealBasis:
+		return complex(cos, sin)
+	case k.m > 0:
+		return complex(math.Sqrt2*cos, 0)
+	}
+	return complex(math.Sqrt2*sin, 0)
+}
+
+// tabTerm is a term of a tabulated wave function.
+type tabTerm struct {
+	tabKey
+	// Coefficient of the term.
+	c complex128
+	// Table of the polar part Θ_{l|m|}(θ).
+	polar *table
+}
+
+// tabulateInto adds the hydrogen-like orbitals of the wave function, scaled by
+// c, to the coefficients of tabulated orbitals. Orbitals keep their basis.
+func tabulateInto(coeffs map[tabKey]complex128, w Wavefunction, c complex128) bool {
+	switch w := w.(type) {
+	case *Hydrogenic:
+		basis := w.Basis
+		if w.M == 0 {
+			// The complex and real m=0 orbitals coincide.
+			basis = RealBasis
+		}
+		coeffs[tabKey{w.Z, w.N, w.L, w.M, basis}] += c
+		return true
+	case *Combination:
+		for i, t := range w.Terms {
+			if !tabulateInto(coeffs, t, c*w.Coeffs[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// polarFunc returns the polar part Θ_{l|m|}(θ) = N_l^|m| P_l^|m|(cos θ) of the
+// normalized spherical harmonic Y_l^m(θ, φ), without the Condon-Shortley phase
+// (see sphericalHarmonic). The sign of sin^|m| θ is retained for θ outside of
+// [0, π], such that Θ is smooth at the poles for interpolation.
+func polarFunc(l, absM int, theta float64) float64 {
+	lnorm := math.Log(float64(2*l+1)/(4.0*math.Pi)) + lgamma(l-absM+1) - lgamma(l+absM+1)
+	y := math.Exp(lnorm/2.0) * assocLegendre(l, absM, math.Cos(theta))
+	if math.Sin(theta) < 0 && absM%2 == 1 {
+		y = -y
+	}
+	return y
+}
+
+// table is a table of a smooth function f(x), sampled at uniformly spaced
+// points x_i = x0 + i h, for -1 <= i <= n+1.
+type table struct {
+	// Start and step of sample points.
+	x0, h float64
+	// Number of intervals between x0 and the end of the table.
+	n int
+	// Function values f(x_{i-1}), indexed by i.
+	vals []float64
+}
+
+// newTable returns a table of f(x) for x in [x0, x1], with a step of at most h.
+// The function f is also evaluated one step outside of [x0, x1].
+func newTable(x0, x1, h float64, f func(x float64) float64) *table {
+	n := int(math.Ceil((x1 - x0) / h))
+	if n < 1 {
+		n = 1
+	}
+	h = (x1 - x0) / float64(n)
+	t := &table{
+		x0:   x0,
+		h:    h,
+		n:    n,
+		vals: make([]float64, n+3),
+	}
+	for i := range t.vals {
+		t.vals[i] = f(x0 + float64(i-1)*h)
+	}
+	return t
+}
+
+// at returns f(x) by cubic Lagrange interpolation of the four table entries
+// surrounding x. The boolean return value indicates whether x is within the
+// table.
+func (t *table) at(x float64) (float64, bool) {
+	u := (x - t.x0) / t.h
+	if !(u >= -1e-9 && u <= float64(t.n)+1e-9) {
+		return 0, false
+	}
+	i := int(u)
+	if i >= t.n {
+		i = t.n - 1
+	}
+	f := u - float64(i)
+	// Lagrange basis polynomials of the nodes -1, 0, 1 and 2 at f.
+	w0 := -f * (f - 1) * (f - 2) / 6
+	w1 := (f + 1) * (f - 1) * (f - 2) / 2
+	w2 := -(f + 1) * f * (f - 2) / 2
+	w3 := (f + 1) * f * (f - 1) / 6
+	v := t.vals[i:]
+	return w0*v[0] + w1*v[1] + w2*v[2] + w3*v[3], true
+}
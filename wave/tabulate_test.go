@@ -0,0 +1,145 @@
+package wave
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// tabulateTolerance is the maximum error of tabulated wave functions, relative to
+// the maximum of |psi|; i.e. the interpolation error of the tables (see
+// Tabulate). The observed error of the test cases is below 10^{-10}.
+const tabulateTolerance = 1e-8
+
+func TestTabulate(t *testing.T) {
+	for _, w := range tabulateGolden(t) {
+		Z, n, _, _ := w.QuantumNumbers()
+		if n == -1 {
+			n = 5
+		}
+		rmax := 4 * float64(n*n) * BohrRadius / float64(Z)
+		tab, err := Tabulate(w, rmax)
+		if err != nil {
+			t.Errorf("%s: unable to tabulate; %v", w.Label(), err)
+			continue
+		}
+		// Evaluate within and beyond the tabulated radius.
+		ps := sphericalGrid(1.5 * rmax)
+		max := 0.0
+		for _, p := range ps {
+			max = math.Max(max, cmplx.Abs(w.Psi(p[0], p[1], p[2])))
+		}
+		for _, p := range ps {
+			want := w.Psi(p[0], p[1], p[2])
+			if got := tab.Psi(p[0], p[1], p[2]); !(cmplx.Abs(got-want) <= tabulateTolerance*max) {
+				t.Errorf("%s: psi mismatch at (rho, theta, phi) = %v; expected %g, got %g", w.Label(), p, want, got)
+				break
+			}
+			if got := tab.Ray(p[1], p[2])(p[0]); !(cmplx.Abs(got-want) <= tabulateTolerance*max) {
+				t.Errorf("%s: psi mismatch along ray at (rho, theta, phi) = %v; expected %g, got %g", w.Label(), p, want, got)
+				break
+			}
+		}
+	}
+}
+
+// tabulateGolden returns the wave functions of the tabulation tests; the
+// orbitals up to n=5 in both bases with Z=1 and Z=3, hybrid orbitals, and
+// rotated and complex linear combinations.
+func tabulateGolden(t testing.TB) []Wavefunction {
+	var ws []Wavefunction
+	for _, Z := range []int{1, 3} {
+		for n := 1; n <= 5; n++ {
+			for l := 0; l < n; l++ {
+				for m := -l; m <= l; m++ {
+					for _, basis := range []Basis{RealBasis, ComplexBasis} {
+						w, err := NewHydrogenic(basis, Z, n, l, m)
+						if err != nil {
+							t.Fatalf("%+v", err)
+						}
+						ws = append(ws, w)
+					}
+				}
+			}
+		}
+	}
+	for _, name := range Hybridizations() {
+		hs, err := Hybrids(name, 1)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		for _, h := range hs {
+			ws = append(ws, h)
+		}
+	}
+	r, err := Rotate(ws[len(ws)-1], EulerRotation(0.3, 1.1, -0.7))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	a, err := NewHydrogenic(ComplexBasis, 1, 3, 2, -1)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	b, err := NewHydrogenic(RealBasis, 1, 4, 1, 1)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	c, err := LinearCombination([]complex128{complex(0.6, 0.2), complex(0, -0.5)}, []Wavefunction{a, b})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return append(ws, r, c)
+}
+
+// BenchmarkTabulate benchmarks evaluation of psi directly, through tables, and
+// through tables along rays (as by the spherical sampler, with 100 radial
+// samples per ray).
+func BenchmarkTabulate(b *testing.B) {
+	var ws []Wavefunction
+	for _, nlm := range [][3]int{{1, 0, 0}, {3, 2, 0}, {5, 3, 1}} {
+		w, err := NewHydrogenic(RealBasis, 1, nlm[0], nlm[1], nlm[2])
+		if err != nil {
+			b.Fatalf("%+v", err)
+		}
+		ws = append(ws, w)
+	}
+	hs, err := Hybrids("sp3", 1)
+	if err != nil {
+		b.Fatalf("%+v", err)
+	}
+	ws = append(ws, hs[0])
+	const nr = 100
+	for _, w := range ws {
+		_, n, _, _ := w.QuantumNumbers()
+		rmax := 4 * float64(n*n) * BohrRadius
+		tab, err := Tabulate(w, rmax)
+		if err != nil {
+			b.Fatalf("%+v", err)
+		}
+		// Evaluate nr points per op, along the ray of a fixed direction.
+		const theta, phi = 1.0, 0.5
+		b.Run(fmt.Sprintf("%s/direct", w.Label()), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < nr; j++ {
+					w.Psi(rmax*float64(j)/nr, theta, phi)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("%s/tabulated", w.Label()), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < nr; j++ {
+					tab.Psi(rmax*float64(j)/nr, theta, phi)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("%s/ray", w.Label()), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ray := tab.Ray(theta, phi)
+				for j := 0; j < nr; j++ {
+					ray(rmax * float64(j) / nr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Package wave implements hydrogen-like wave functions and hybrid orbitals.
+//
+// Wave functions are evaluated at spherical (rho, theta, phi)-coordinates, where
+// rho is the radial distance, theta is the inclination and phi is the azimuth.
+//
+// Quantities are expressed in atomic units; lengths in Bohr radii, a_0, psi in
+// a_0^{-3/2} and energies in Hartree, E_h. Thus psi is of order 1 for all
+// orbitals, and quantities are converted to other units (e.g. picometer and
+// electronvolt) at the I/O boundary, as in
+//
+//    r_pm := r / wave.Picometer
+//    E_eV := E / wave.ElectronVolt
+package wave
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// BohrRadius is the atomic unit of length, a_0.
+const BohrRadius = 1.0 // 52.9 pm
+
+// Convert picometer to atomic units of length.
+const Picometer = BohrRadius / 52.9177210903 // 1 pm = 0.0189 a_0
+
+// Hartree is the atomic unit of energy, E_h.
+const Hartree = 1.0 // 27.2 eV
+
+// Convert electronvolt to atomic units of energy.
+const ElectronVolt = Hartree / 27.211386245988 // 1 eV = 0.0367 E_h
+
+// Rydberg is the Rydberg unit of energy, E_h/2.
+const Rydberg = Hartree / 2 // 13.6 eV
+
+// CartesianFromSpherical returns the Cartesian (x, y, z)-coordinate
+// corresponding to the given spherical (rho, theta, phi)-coordinate, where
+// theta is the inclination and phi is the azimuth.
+func CartesianFromSpherical(rho, theta, phi float64) (x, y, z float64) {
+	x = rho * math.Sin(theta) * math.Cos(phi)
+	y = rho * math.Sin(theta) * math.Sin(phi)
+	z = rho * math.Cos(theta)
+	return x, y, z
+}
+
+// SphericalFromCartesian returns the spherical (rho, theta, phi)-coordinate
+// corresponding to the given Cartesian (x, y, z)-coordinate, where theta is the
+// inclination and phi is the azimuth.
+//
+// ref: https://en.wikipedia.org/wiki/Spherical_coordinate_system#Cartesian_coordinates
+func SphericalFromCartesian(x, y, z float64) (rho, theta, phi float64) {
+	rho = math.Sqrt(x*x + y*y + z*z)
+	theta = math.Atan2(math.Sqrt(x*x+y*y), z)
+	phi = math.Atan2(y, x)
+	return rho, theta, phi
+}
+
+// RadialProb returns the radial probability based on the given radius, r, and
+// psi for the s-orbital.
+//
+// NOTE: the returned probability is not yet normalized.
+func RadialProb(r, psi float64) float64 {
+	// area of sphere.
+	area := 4.0 * math.Pi * math.Pow(r, 2)
+	// radial probability = area * psi^2.
+	return area * math.Pow(psi, 2)
+}
+
+// PsiFunc is a real-valued wave function, returning psi at the spherical (rho,
+// theta, phi)-coordinate.
+type PsiFunc func(rho, theta, phi float64) float64
+
+// ComplexPsiFunc is a complex-valued wave function, returning psi at the
+// spherical (rho, theta, phi)-coordinate. In contrast to PsiFunc, the phase of
+// psi is retained.
+type ComplexPsiFunc func(rho, theta, phi float64) complex128
+
+// RayFunc is a complex-valued wave function evaluated along rays from the
+// origin, returning psi along the ray in the direction of the inclination theta
+// and azimuth phi, as a function of the radius rho. Evaluating a wave function
+// along rays permits the angular part of separable wave functions to be
+// evaluated once per direction (see Tabulated.Ray).
+type RayFunc func(theta, phi float64) func(rho float64) complex128
+
+// Rays returns the complex-valued wave function evaluated along rays from the
+// origin, corresponding to the given complex-valued wave function.
+func Rays(Psi ComplexPsiFunc) RayFunc {
+	return func(theta, phi float64) func(rho float64) complex128 {
+		return func(rho float64) complex128 {
+			return Psi(rho, theta, phi)
+		}
+	}
+}
+
+// ToComplex returns the complex-valued wave function corresponding to the given
+// real-valued wave function.
+func ToComplex(Psi PsiFunc) ComplexPsiFunc {
+	return func(rho, theta, phi float64) complex128 {
+		return complex(Psi(rho, theta, phi), 0)
+	}
+}
+
+// Superposition returns the complex-valued wave function of the superposition
+// sum_i c_i psi_i, based on the given coefficients, c_i, and wave functions,
+// psi_i. Since the phase of each wave function is retained, the wave functions
+// interfere as expected.
+//
+// An error is returned if the number of coefficients and wave functions differ.
+func Superposition(coeffs []complex128, Psis []ComplexPsiFunc) (ComplexPsiFunc, error) {
+	if len(coeffs) != len(Psis) {
+		return nil, errors.Errorf("mismatch between number of coefficients (%d) and wave functions (%d)", len(coeffs), len(Psis))
+	}
+	Psi := func(rho, theta, phi float64) complex128 {
+		var psi complex128
+		for i, Psi := range Psis {
+			psi += coeffs[i] * Psi(rho, theta, phi)
+		}
+		return psi
+	}
+	return Psi, nil
+}
+
+// Orbitals returns the psi function of the hydrogen-like orbital with the
+// specified nuclear charge, Z, principal quantum number, n, azimuthal quantum
+// number, l, and magnetic quantum number, m.
+//
+// NOTE: only the real part of psi is returned; use ComplexOrbitals to retain
+// the phase of psi.
+//
+//    rho (ρ):   radial distance (radius)
+//    theta (θ): inclination (angular)
+//    phi (φ):   azimuth (angular)
+//
+// An error is returned if the quantum numbers are invalid (see
+// InvalidQuantumNumberError) or unsupported (see UnsupportedQuantumNumberError).
+func Orbitals(Z, n, l, m int) (PsiFunc, error) {
+	if err := CheckQuantumNumbers(Z, n, l, m); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return psiOrbital(Z, n, l, m), nil
+}
+
+// ComplexOrbitals returns the complex-valued psi function of the hydrogen-like
+// orbital with the specified nuclear charge, Z, principal quantum number, n,
+// azimuthal quantum number, l, and magnetic quantum number, m.
+//
+// An error is returned if the quantum numbers are invalid or unsupported.
+func ComplexOrbitals(Z, n, l, m int) (ComplexPsiFunc, error) {
+	if err := CheckQuantumNumbers(Z, n, l, m); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return psiComplexOrbital(Z, n, l, m), nil
+}
+
+// RealOrbitals returns the psi function of the real hydrogen-like orbital (e.g.
+// p_x, p_y, d_xy, d_x2-y2) with the specified nuclear charge, Z, principal
+// quantum number, n, azimuthal quantum number, l, and magnetic quantum number,
+// m.
+//
+//    p_x: m=+1    d_xy:    m=-2
+//    p_y: m=-1    d_yz:    m=-1
+//    p_z: m=0     d_z2:    m=0
+//                 d_xz:    m=+1
+//                 d_x2-y2: m=+2
+//
+// An error is returned if the quantum numbers are invalid or unsupported.
+func RealOrbitals(Z, n, l, m int) (PsiFunc, error) {
+	if err := CheckQuantumNumbers(Z, n, l, m); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return psiRealOrbital(Z, n, l, m), nil
+}
+
+// Basis specifies the basis of the angular part of orbitals.
+type Basis uint8
+
+// Orbital bases.
+const (
+	// ComplexBasis is the basis of eigenstates of L_z with magnetic quantum
+	// number m, with e^{imφ} angular dependence.
+	ComplexBasis Basis = iota
+	// RealBasis is the basis of real (tesseral) orbitals, e.g. p_x, p_y, p_z,
+	// d_z2, d_xz, d_yz, d_xy and d_x2-y2.
+	RealBasis
+)
+
+// Orbital returns the complex-valued psi function of the (n, l, m)-orbital with
+// nuclear charge Z in the specified basis.
+//
+// An error is returned if the quantum numbers are invalid or unsupported.
+func Orbital(basis Basis, Z, n, l, m int) (ComplexPsiFunc, error) {
+	switch basis {
+	case ComplexBasis:
+		return ComplexOrbitals(Z, n, l, m)
+	case RealBasis:
+		Psi, err := RealOrbitals(Z, n, l, m)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return ToComplex(Psi), nil
+	}
+	return nil, errors.Errorf("support for basis %d not yet implemented", basis)
+}
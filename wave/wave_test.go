@@ -0,0 +1,33 @@
+package wave
+
+import (
+	"math/cmplx"
+	"testing"
+)
+
+func TestSuperposition(t *testing.T) {
+	a, err := NewHydrogenic(ComplexBasis, 1, 2, 1, 1)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	b, err := NewHydrogenic(ComplexBasis, 1, 2, 1, -1)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	coeffs := []complex128{complex(0.6, 0), complex(0, 0.8)}
+	Psi, err := Superposition(coeffs, []ComplexPsiFunc{a.Psi, b.Psi})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, p := range sphericalGrid(10) {
+		want := coeffs[0]*a.Psi(p[0], p[1], p[2]) + coeffs[1]*b.Psi(p[0], p[1], p[2])
+		if got := Psi(p[0], p[1], p[2]); cmplx.Abs(got-want) > 1e-15 {
+			t.Errorf("psi mismatch at (rho, theta, phi) = %v; expected %v, got %v", p, want, got)
+			break
+		}
+	}
+	// Mismatch between number of coefficients and wave functions.
+	if _, err := Superposition(coeffs, []ComplexPsiFunc{a.Psi}); err == nil {
+		t.Errorf("expected error for mismatched number of coefficients, got nil")
+	}
+}
@@ -0,0 +1,120 @@
+package wave
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// Wavefunction is a wave function of an electron bound to a nucleus, with its
+// quantum numbers, radial and angular parts, energy and normalization.
+type Wavefunction interface {
+	// QuantumNumbers returns the nuclear charge, Z, principal quantum number, n,
+	// azimuthal quantum number, l, and magnetic quantum number, m, of the wave
+	// function. Quantum numbers without definite value (e.g. l and m of hybrid
+	// orbitals) are -1.
+	QuantumNumbers() (Z, n, l, m int)
+	// Label returns the label of the wave function (e.g. "3d_z2" or "sp3_0").
+	Label() string
+	// Psi returns psi at the spherical (rho, theta, phi)-coordinate.
+	Psi(rho, theta, phi float64) complex128
+	// Radial returns the radial function R(r) at the radius r, such that r^2
+	// R(r)^2 is the radial probability density.
+	Radial(r float64) float64
+	// Angular returns the angular function Y(θ, φ) at the inclination theta
+	// and azimuth phi.
+	Angular(theta, phi float64) complex128
+	// Separable reports whether psi(r, θ, φ) = R(r) Y(θ, φ).
+	Separable() bool
+	// Energy returns the energy (expectation value) with unit E_h.
+	Energy() float64
+	// Norm returns the norm <psi|psi>^{1/2} of the wave function.
+	Norm() float64
+}
+
+// Hydrogenic is a hydrogen-like orbital.
+type Hydrogenic struct {
+	// Nuclear charge.
+	Z int
+	// Principal quantum number.
+	N int
+	// Azimuthal quantum number.
+	L int
+	// Magnetic quantum number.
+	M int
+	// Basis of the angular part.
+	Basis Basis
+}
+
+// NewHydrogenic returns the hydrogen-like (n, l, m)-orbital with nuclear charge
+// Z in the specified basis.
+//
+// An error is returned if the quantum numbers are invalid or unsupported.
+func NewHydrogenic(basis Basis, Z, n, l, m int) (*Hydrogenic, error) {
+	if err := CheckQuantumNumbers(Z, n, l, m); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	switch basis {
+	case ComplexBasis, RealBasis:
+		// valid basis.
+	default:
+		return nil, errors.Errorf("support for basis %d not yet implemented", basis)
+	}
+	h := &Hydrogenic{Z: Z, N: n, L: l, M: m, Basis: basis}
+	return h, nil
+}
+
+// QuantumNumbers returns the nuclear charge, Z, and the quantum numbers n, l
+// and m of the orbital.
+func (h *Hydrogenic) QuantumNumbers() (Z, n, l, m int) {
+	return h.Z, h.N, h.L, h.M
+}
+
+// Label returns the orbital specification of the orbital (e.g. "3d_z2" or
+// "4f-3").
+func (h *Hydrogenic) Label() string {
+	spec := Spec{N: h.N, L: h.L, M: h.M, Basis: h.Basis}
+	return spec.String()
+}
+
+// Psi returns psi at the spherical (rho, theta, phi)-coordinate.
+func (h *Hydrogenic) Psi(rho, theta, phi float64) complex128 {
+	return complex(h.Radial(rho), 0) * h.Angular(theta, phi)
+}
+
+// Radial returns the radial function R_{nl}(r) at the radius r.
+func (h *Hydrogenic) Radial(r float64) float64 {
+	return radialFunc(h.Z, h.N, h.L, r)
+}
+
+// Angular returns the spherical harmonic Y_l^m(θ, φ), or the real spherical
+// harmonic S_l^m(θ, φ) in the real basis, at the inclination theta and azimuth
+// phi.
+func (h *Hydrogenic) Angular(theta, phi float64) complex128 {
+	if h.Basis == RealBasis {
+		return complex(realSphericalHarmonic(h.L, h.M, theta, phi), 0)
+	}
+	return sphericalHarmonic(h.L, h.M, theta, phi)
+}
+
+// Separable reports whether psi(r, θ, φ) = R(r) Y(θ, φ), which always holds for
+// hydrogen-like orbitals.
+func (h *Hydrogenic) Separable() bool {
+	return true
+}
+
+// Energy returns the energy eigenvalue -Ry Z^2/n^2 of the orbital with unit E_h.
+func (h *Hydrogenic) Energy() float64 {
+	return -Rydberg * math.Pow(float64(h.Z), 2) / math.Pow(float64(h.N), 2)
+}
+
+// Norm returns the norm of the orbital, which is normalized.
+func (h *Hydrogenic) Norm() float64 {
+	return 1
+}
+
+// RadialProbability returns the radial probability density r^2 R(r)^2 of the
+// wave function at the radius r.
+func RadialProbability(w Wavefunction, r float64) float64 {
+	return math.Pow(r, 2) * math.Pow(w.Radial(r), 2)
+}